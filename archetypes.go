@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/tui"
+)
+
+var archetypesCmd = &cobra.Command{
+	Use:   "archetypes",
+	Short: "Browse workflow archetypes and their research data",
+}
+
+var archetypesShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show success stats, recommendations and tips for an archetype",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		patterns, err := data.LoadPatterns()
+		if err != nil {
+			return fmt.Errorf("loading patterns: %w", err)
+		}
+
+		a, err := patterns.LookupArchetype(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig(config.Config{})
+		if err != nil {
+			return err
+		}
+		theme, _, err := loadUIConfig(cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), tui.NewStyles(theme).RenderArchetypeDetails(a))
+		return nil
+	},
+}
+
+func init() {
+	archetypesCmd.AddCommand(archetypesShowCmd)
+	rootCmd.AddCommand(archetypesCmd)
+}
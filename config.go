@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+	"github.com/ashleywolf/gh-aw-create/internal/lint"
+	"github.com/ashleywolf/gh-aw-create/internal/policy"
+	"github.com/ashleywolf/gh-aw-create/internal/tui"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show the effective settings and where each came from",
+	Long: fmt.Sprintf(`Show the effective settings. Each layer overrides the ones before it:
+
+  1. the user's config.yaml (see the path below)
+  2. the repository's %s
+  3. %s* environment variables, e.g. %sENGINE
+  4. command-line flags`, config.RepoFile, config.EnvPrefix, config.EnvPrefix),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(config.Config{})
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		if p, err := config.Path(); err == nil {
+			fmt.Fprintf(out, "User config: %s\n", p)
+		}
+		root, err := repoRoot()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Repo config: %s\n\n", filepath.Join(root, config.RepoFile))
+
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+		for _, s := range cfg.Settings() {
+			value := s.Value
+			if value == "" {
+				value = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", s.Name, value, s.Source)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
+
+// repoRoot returns the root of the repository the current directory is
+// in, where its config and policy live.
+func repoRoot() (string, error) {
+	root, err := config.RepoRoot(".")
+	if err != nil {
+		return "", fmt.Errorf("finding the repository root: %w", err)
+	}
+	return root, nil
+}
+
+// inRepo resolves a path from the config, such as output_dir, against the
+// repository root. Absolute paths are kept.
+func inRepo(root, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(root, path)
+}
+
+// loadConfig merges the config files, environment and flags for the
+// repository the current directory is in.
+func loadConfig(flags config.Config) (config.Effective, error) {
+	root, err := repoRoot()
+	if err != nil {
+		return config.Effective{}, err
+	}
+	cfg, err := config.Load(root, flags)
+	if err != nil {
+		return config.Effective{}, fmt.Errorf("loading config: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadUIConfig resolves the theme and keymap settings.
+func loadUIConfig(cfg config.Effective) (tui.Theme, tui.KeyMap, error) {
+	ascii := cfg.ASCII != nil && *cfg.ASCII
+	theme, err := tui.ResolveTheme(cfg.Theme, ascii)
+	if err != nil {
+		return tui.Theme{}, tui.KeyMap{}, fmt.Errorf("loading config: %w", cfg.Errorf("theme", "%s", err))
+	}
+	keys, err := tui.NewKeyMap(cfg.Keymap, cfg.Keys)
+	if err != nil {
+		return tui.Theme{}, tui.KeyMap{}, fmt.Errorf("loading config: %w", err)
+	}
+	return theme, keys, nil
+}
+
+// memoryKinds maps the config's memory names to generator kinds.
+var memoryKinds = map[string]string{
+	"":      generator.MemoryNone,
+	"none":  generator.MemoryNone,
+	"cache": generator.MemoryCache,
+	"repo":  generator.MemoryRepo,
+}
+
+// wizardOptions checks the settings that pre-fill the wizard and converts
+// them to its options, along with the repository's policy.
+func wizardOptions(p *data.Patterns, cfg config.Effective) (tui.Options, error) {
+	engine := generator.EngineConfig{ID: cfg.Engine, Model: cfg.Model, MaxTurns: cfg.MaxTurns, Command: cfg.Command}
+	if err := engine.Validate(); err != nil {
+		return tui.Options{}, fmt.Errorf("loading config: %w", cfg.Errorf("engine", "%s", err))
+	}
+
+	memory, ok := memoryKinds[cfg.Memory]
+	if !ok {
+		return tui.Options{}, fmt.Errorf("loading config: %w", cfg.Errorf("memory", "unknown memory kind %q (want none, cache or repo)", cfg.Memory))
+	}
+
+	for id, triggers := range cfg.Triggers {
+		if _, ok := p.FindArchetype(id); !ok {
+			return tui.Options{}, fmt.Errorf("loading config: %w", cfg.Errorf("triggers."+id, "unknown archetype %q", id))
+		}
+		for _, t := range triggers {
+			if _, ok := p.Trigger(t); !ok {
+				return tui.Options{}, fmt.Errorf("loading config: %w", cfg.Errorf("triggers."+id, "unknown trigger %q", t))
+			}
+		}
+	}
+
+	severities, err := lintSeverities(cfg)
+	if err != nil {
+		return tui.Options{}, fmt.Errorf("loading config: %w", err)
+	}
+
+	root, err := repoRoot()
+	if err != nil {
+		return tui.Options{}, err
+	}
+	pol, err := loadPolicy()
+	if err != nil {
+		return tui.Options{}, err
+	}
+
+	return tui.Options{
+		Dir:       root,
+		Engine:    engine,
+		Context:   cfg.Context,
+		Memory:    memory,
+		Triggers:  cfg.Triggers,
+		OutputDir: cfg.OutputDir,
+		Lint:      severities,
+		Policy:    pol,
+	}, nil
+}
+
+// loadPolicy reads the policy of the repository the current directory is
+// in, so running from a subdirectory doesn't skip it.
+func loadPolicy() (*policy.Policy, error) {
+	root, err := repoRoot()
+	if err != nil {
+		return nil, err
+	}
+	pol, err := policy.Load(root)
+	if err != nil {
+		return nil, fmt.Errorf("loading policy: %w", err)
+	}
+	return pol, nil
+}
+
+// lintSeverities checks the lint settings, naming where a bad one was set.
+func lintSeverities(cfg config.Effective) (lint.Severities, error) {
+	for id, severity := range cfg.Lint {
+		if _, err := lint.ParseSeverities(map[string]string{id: severity}); err != nil {
+			return nil, fmt.Errorf("%w (from %s)", err, cfg.Source("lint."+id))
+		}
+	}
+	return lint.ParseSeverities(cfg.Lint)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ashleywolf/gh-aw-create/internal/draft"
+)
+
+var draftsCmd = &cobra.Command{
+	Use:   "drafts",
+	Short: "List saved wizard sessions",
+	Long:  "List wizard sessions saved for resuming. A session is saved per repository while the wizard runs and removed once the workflow is written.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		drafts, err := draft.List()
+		if err != nil {
+			return err
+		}
+		out := cmd.OutOrStdout()
+		if len(drafts) == 0 {
+			fmt.Fprintln(out, "No saved drafts")
+			return nil
+		}
+		for _, d := range drafts {
+			fmt.Fprintf(out, "%s  %s  %s\n", d.Updated.Format("2006-01-02 15:04"), d.Repo, d.Summary)
+		}
+		return nil
+	},
+}
+
+var draftsDeleteAll bool
+
+var draftsDeleteCmd = &cobra.Command{
+	Use:   "delete [repo]",
+	Short: "Delete the saved session for a repository (default: the one the current directory is in)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if draftsDeleteAll {
+			drafts, err := draft.List()
+			if err != nil {
+				return err
+			}
+			for _, d := range drafts {
+				if err := draft.Delete(d.Repo); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d draft(s)\n", len(drafts))
+			return nil
+		}
+
+		dir := "."
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		repo, err := draft.RepoPath(dir)
+		if err != nil {
+			return err
+		}
+		if _, ok, err := draft.Load(repo); err == nil && !ok {
+			return fmt.Errorf("no draft saved for %s", repo)
+		}
+		if err := draft.Delete(repo); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted draft for %s\n", repo)
+		return nil
+	},
+}
+
+func init() {
+	draftsDeleteCmd.Flags().BoolVar(&draftsDeleteAll, "all", false, "delete every saved draft")
+	draftsCmd.AddCommand(draftsDeleteCmd)
+	rootCmd.AddCommand(draftsCmd)
+}
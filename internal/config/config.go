@@ -0,0 +1,335 @@
+// Package config loads gh-aw-create's settings. They are layered, each
+// layer overriding the ones before it: the user's config.yaml under the
+// gh-aw-create directory in the user config directory, the repository's
+// .github/aw-create.yaml, GH_AW_CREATE_* environment variables and
+// command-line flags.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is one layer of settings. Empty fields leave the setting to the
+// layers below.
+type Config struct {
+	// Keymap is the keybinding preset: default, vim or emacs.
+	Keymap string `yaml:"keymap"`
+	// Keys rebinds single actions, e.g. quit: [ctrl+q].
+	Keys map[string][]string `yaml:"keys"`
+	// Theme is the color theme: auto, dark, light, high-contrast or
+	// monochrome.
+	Theme string `yaml:"theme"`
+	// ASCII replaces emoji and box-drawing characters.
+	ASCII *bool `yaml:"ascii"`
+
+	// Context is the project context every workflow starts with.
+	Context string `yaml:"context"`
+	// Memory is the memory kind new workflows start with: none, cache or
+	// repo.
+	Memory string `yaml:"memory"`
+	// Triggers replaces an archetype's recommended triggers, keyed by
+	// archetype ID.
+	Triggers map[string][]string `yaml:"triggers"`
+
+	// Engine, Model and MaxTurns pre-select the AI engine.
+	Engine   string `yaml:"engine"`
+	Model    string `yaml:"model"`
+	MaxTurns int    `yaml:"max_turns"`
+	// Command is the agent invocation for the custom engine.
+	Command string `yaml:"command"`
+
+	// OutputDir is where workflows are written.
+	OutputDir string `yaml:"output_dir"`
+	// Lint sets a lint rule's severity by rule ID: error, warning or off.
+	Lint map[string]string `yaml:"lint"`
+}
+
+// Default output directory, used when no layer sets one.
+const DefaultOutputDir = ".github/workflows"
+
+// RepoFile is the repository's settings file, relative to its root.
+const RepoFile = ".github/aw-create.yaml"
+
+// EnvPrefix prefixes the environment variables that set scalar settings,
+// e.g. GH_AW_CREATE_ENGINE.
+const EnvPrefix = "GH_AW_CREATE_"
+
+// Path returns the user's settings file.
+func Path() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-aw-create", "config.yaml"), nil
+}
+
+// RepoRoot returns the root of the repository containing dir: the
+// nearest directory at or above it with a .git entry, as an absolute path.
+// Outside a repository it returns dir itself.
+func RepoRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for d := abs; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			return d, nil
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return abs, nil
+		}
+		d = parent
+	}
+}
+
+// Layer is a set of settings and where they came from.
+type Layer struct {
+	// Source names the layer for display, e.g. a file path or "flag".
+	Source string
+	Config Config
+}
+
+// Effective is the result of merging the layers.
+type Effective struct {
+	Config
+	// Sources maps each setting that a layer set to that layer's source.
+	// Map settings are keyed per entry, e.g. "keys.quit".
+	Sources map[string]string
+}
+
+// Load merges the user's file, the repository's file under repo, the
+// environment and flags, in increasing precedence. Missing files are
+// skipped.
+func Load(repo string, flags Config) (Effective, error) {
+	var layers []Layer
+	if p, err := Path(); err == nil {
+		c, ok, err := LoadFile(p)
+		if err != nil {
+			return Effective{}, err
+		}
+		if ok {
+			layers = append(layers, Layer{p, c})
+		}
+	}
+	repoFile := filepath.Join(repo, RepoFile)
+	c, ok, err := LoadFile(repoFile)
+	if err != nil {
+		return Effective{}, err
+	}
+	if ok {
+		layers = append(layers, Layer{repoFile, c})
+	}
+	env, err := FromEnv(os.Getenv)
+	if err != nil {
+		return Effective{}, err
+	}
+	layers = append(layers, Layer{"env", env}, Layer{"flag", flags})
+	return Merge(layers...), nil
+}
+
+// LoadFile reads a settings file, reporting false if it doesn't exist.
+func LoadFile(path string) (Config, bool, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Config{}, false, nil
+	}
+	if err != nil {
+		return Config{}, false, err
+	}
+	var c Config
+	if err := DecodeStrict(data, &c); err != nil {
+		return Config{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, true, nil
+}
+
+// DecodeStrict decodes a YAML file's contents into v. Unknown fields are
+// errors so a misspelt key isn't silently ignored, and an empty file
+// decodes to nothing.
+func DecodeStrict(data []byte, v any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// FromEnv reads the scalar settings from GH_AW_CREATE_* variables, e.g.
+// GH_AW_CREATE_MAX_TURNS for max_turns.
+func FromEnv(getenv func(string) string) (Config, error) {
+	var c Config
+	for _, s := range scalars {
+		name := EnvPrefix + strings.ToUpper(s.name)
+		v := getenv(name)
+		if v == "" {
+			continue
+		}
+		if err := s.parse(&c, v); err != nil {
+			return Config{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+// Merge applies the layers in order, later ones overriding earlier ones.
+func Merge(layers ...Layer) Effective {
+	e := Effective{Sources: make(map[string]string)}
+	for _, l := range layers {
+		for _, s := range scalars {
+			if s.set(l.Config) {
+				s.copy(&e.Config, l.Config)
+				e.Sources[s.name] = l.Source
+			}
+		}
+		e.Keys = mergeMap(e.Keys, l.Config.Keys, "keys", l.Source, e.Sources)
+		e.Triggers = mergeMap(e.Triggers, l.Config.Triggers, "triggers", l.Source, e.Sources)
+		e.Lint = mergeMap(e.Lint, l.Config.Lint, "lint", l.Source, e.Sources)
+	}
+	return e
+}
+
+// mergeMap overrides dst's entries with src's, recording each entry's
+// source as prefix.key.
+func mergeMap[V any](dst, src map[string]V, prefix, source string, sources map[string]string) map[string]V {
+	for k, v := range src {
+		if dst == nil {
+			dst = make(map[string]V)
+		}
+		dst[k] = v
+		sources[prefix+"."+k] = source
+	}
+	return dst
+}
+
+// Source returns where a setting came from, or "default" when no layer set
+// it.
+func (e Effective) Source(name string) string {
+	if s, ok := e.Sources[name]; ok {
+		return s
+	}
+	return "default"
+}
+
+// Errorf reports a problem with a setting, naming where it was set.
+func (e Effective) Errorf(name, format string, args ...any) error {
+	return fmt.Errorf("%s (from %s): %s", name, e.Source(name), fmt.Sprintf(format, args...))
+}
+
+// Setting is one effective value, for display.
+type Setting struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+// Settings lists every scalar setting followed by the entries of the map
+// settings, with their values and sources.
+func (e Effective) Settings() []Setting {
+	var out []Setting
+	for _, s := range scalars {
+		value := s.def
+		if s.set(e.Config) {
+			value = s.show(e.Config)
+		}
+		out = append(out, Setting{s.name, value, e.Source(s.name)})
+	}
+	for _, name := range sortedKeys(e.Keys) {
+		out = append(out, Setting{"keys." + name, strings.Join(e.Keys[name], ", "), e.Source("keys." + name)})
+	}
+	for _, name := range sortedKeys(e.Triggers) {
+		out = append(out, Setting{"triggers." + name, strings.Join(e.Triggers[name], ", "), e.Source("triggers." + name)})
+	}
+	for _, name := range sortedKeys(e.Lint) {
+		out = append(out, Setting{"lint." + name, e.Lint[name], e.Source("lint." + name)})
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// scalar describes a single-valued setting: how to tell if a layer sets
+// it, copy it, display it and parse it from the environment.
+type scalar struct {
+	name  string
+	def   string
+	set   func(c Config) bool
+	copy  func(dst *Config, src Config)
+	show  func(c Config) string
+	parse func(c *Config, v string) error
+}
+
+// stringSetting builds a scalar for a string field.
+func stringSetting(name, def string, field func(c *Config) *string) scalar {
+	return scalar{
+		name: name,
+		def:  def,
+		set:  func(c Config) bool { return *field(&c) != "" },
+		copy: func(dst *Config, src Config) { *field(dst) = *field(&src) },
+		show: func(c Config) string { return *field(&c) },
+		parse: func(c *Config, v string) error {
+			*field(c) = v
+			return nil
+		},
+	}
+}
+
+// scalars lists the single-valued settings in display order.
+var scalars = []scalar{
+	stringSetting("context", "", func(c *Config) *string { return &c.Context }),
+	stringSetting("memory", "none", func(c *Config) *string { return &c.Memory }),
+	stringSetting("engine", "copilot", func(c *Config) *string { return &c.Engine }),
+	stringSetting("model", "", func(c *Config) *string { return &c.Model }),
+	{
+		name: "max_turns",
+		set:  func(c Config) bool { return c.MaxTurns != 0 },
+		copy: func(dst *Config, src Config) { dst.MaxTurns = src.MaxTurns },
+		show: func(c Config) string { return strconv.Itoa(c.MaxTurns) },
+		parse: func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%q is not a number", v)
+			}
+			c.MaxTurns = n
+			return nil
+		},
+	},
+	stringSetting("command", "", func(c *Config) *string { return &c.Command }),
+	stringSetting("output_dir", DefaultOutputDir, func(c *Config) *string { return &c.OutputDir }),
+	stringSetting("theme", "auto", func(c *Config) *string { return &c.Theme }),
+	{
+		name: "ascii",
+		def:  "false",
+		set:  func(c Config) bool { return c.ASCII != nil },
+		copy: func(dst *Config, src Config) { dst.ASCII = src.ASCII },
+		show: func(c Config) string { return strconv.FormatBool(*c.ASCII) },
+		parse: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%q is not true or false", v)
+			}
+			c.ASCII = &b
+			return nil
+		},
+	},
+	stringSetting("keymap", "default", func(c *Config) *string { return &c.Keymap }),
+}
@@ -0,0 +1,232 @@
+package data
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AntiPattern is a workflow name that performed poorly across public repos.
+type AntiPattern struct {
+	Pattern     string  `json:"pattern"`
+	SuccessRate float64 `json:"success_rate"`
+	ReposSeen   int     `json:"repos_seen"`
+	Reason      string  `json:"reason"`
+}
+
+// Where an anti-pattern was matched.
+const (
+	MatchName   = "name"
+	MatchPrompt = "prompt"
+)
+
+// AntiPatternMatch is an anti-pattern found in a workflow name or prompt.
+type AntiPatternMatch struct {
+	Pattern string
+	In      string
+	Reason  string
+	// Alternative is an archetype to start from that does better than the
+	// average workflow, if any.
+	Alternative *Archetype
+}
+
+func (m AntiPatternMatch) String() string {
+	verb := "resembles"
+	if m.In == MatchPrompt {
+		verb = "mentions"
+	}
+	s := fmt.Sprintf("%s %s %q: %s", m.In, verb, m.Pattern, m.Reason)
+	if m.Alternative != nil {
+		s += fmt.Sprintf(" — start from the %s archetype instead (%.0f%% success)",
+			m.Alternative.Label, m.Alternative.SuccessRate*100)
+	}
+	return s
+}
+
+// nameSimilarity is the minimum similarity for a fuzzy name match.
+const nameSimilarity = 0.85
+
+// MatchAntiPatterns checks a workflow name and prompt against the global
+// anti-pattern list and each archetype's anti-patterns. Names match fuzzily;
+// prompts match when they mention a multi-word pattern as a phrase.
+func (p *Patterns) MatchAntiPatterns(name, prompt string) []AntiPatternMatch {
+	name = normalizeName(name)
+	prompt = " " + strings.Join(nameTokens(prompt), " ") + " "
+
+	var out []AntiPatternMatch
+	seen := make(map[string]bool)
+	check := func(pattern, reason string) {
+		norm := normalizeName(pattern)
+		if seen[norm] {
+			return
+		}
+		tokens := nameTokens(pattern)
+
+		in := ""
+		switch {
+		case name != "" && (similarity(name, norm) >= nameSimilarity ||
+			(len(tokens) > 1 && strings.Contains("-"+name+"-", "-"+norm+"-"))):
+			in = MatchName
+		case len(tokens) > 1 && strings.Contains(prompt, " "+strings.Join(tokens, " ")+" "):
+			in = MatchPrompt
+		default:
+			return
+		}
+		seen[norm] = true
+		out = append(out, AntiPatternMatch{
+			Pattern:     pattern,
+			In:          in,
+			Reason:      reason,
+			Alternative: p.antiPatternAlternative(norm),
+		})
+	}
+
+	for _, ap := range p.aggregateAntiPatterns() {
+		reason := fmt.Sprintf("%.0f%% success across %d repo(s)", ap.SuccessRate*100, ap.ReposSeen)
+		if ap.Reason != "" {
+			reason += " (" + ap.Reason + ")"
+		}
+		check(ap.Pattern, reason)
+	}
+	for _, a := range p.Archetypes {
+		for _, pattern := range a.AntiPatterns {
+			// An anti-pattern spelled like the archetype ID is just the
+			// generic name, not a specific template to avoid.
+			if normalizeName(pattern) == a.ID {
+				continue
+			}
+			check(pattern, fmt.Sprintf("underperformed among %s workflows", a.Label))
+		}
+	}
+	return out
+}
+
+// aggregateAntiPatterns merges global entries for the same pattern seen in
+// several repos. A merged entry drops the reason, which names a single
+// repo.
+func (p *Patterns) aggregateAntiPatterns() []AntiPattern {
+	var out []AntiPattern
+	index := make(map[string]int)
+	for _, ap := range p.AntiPatterns {
+		i, ok := index[ap.Pattern]
+		if !ok {
+			index[ap.Pattern] = len(out)
+			out = append(out, ap)
+			continue
+		}
+		agg := &out[i]
+		total := agg.ReposSeen + ap.ReposSeen
+		agg.SuccessRate = (agg.SuccessRate*float64(agg.ReposSeen) + ap.SuccessRate*float64(ap.ReposSeen)) / float64(total)
+		agg.ReposSeen = total
+		agg.Reason = ""
+	}
+	return out
+}
+
+// antiPatternAlternative picks the archetype a matched pattern belongs to:
+// the one listing it as an anti-pattern, then one whose tips warn about it,
+// then the one sharing the most name tokens. Only archetypes that beat the
+// average workflow are suggested.
+func (p *Patterns) antiPatternAlternative(norm string) *Archetype {
+	baseline := p.averageSuccessRate()
+	for i, a := range p.Archetypes {
+		if a.SuccessRate <= baseline {
+			continue
+		}
+		for _, ap := range a.AntiPatterns {
+			if normalizeName(ap) == norm {
+				return &p.Archetypes[i]
+			}
+		}
+	}
+	for i, a := range p.Archetypes {
+		if a.SuccessRate <= baseline {
+			continue
+		}
+		for _, tip := range a.Tips {
+			if strings.Contains(strings.ToLower(tip), norm) {
+				return &p.Archetypes[i]
+			}
+		}
+	}
+
+	tokens := nameTokens(norm)
+	var best *Archetype
+	bestOverlap := 0
+	for i, a := range p.Archetypes {
+		if a.SuccessRate <= baseline {
+			continue
+		}
+		overlap := 0
+		for _, t := range nameTokens(a.ID + " " + a.Label) {
+			if slices.Contains(tokens, t) {
+				overlap++
+			}
+		}
+		if overlap > bestOverlap {
+			best, bestOverlap = &p.Archetypes[i], overlap
+		}
+	}
+	return best
+}
+
+// averageSuccessRate is the success rate of all workflows, weighting each
+// archetype by its workflow count.
+func (p *Patterns) averageSuccessRate() float64 {
+	var sum float64
+	var count int
+	for _, a := range p.Archetypes {
+		sum += a.SuccessRate * float64(a.Count)
+		count += a.Count
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// nameTokens lowercases s and splits it on anything that isn't a letter or
+// digit.
+func nameTokens(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// normalizeName converts a workflow name to dash-separated lowercase tokens,
+// so that issue_triage, Issue Triage and issue-triage compare equal.
+func normalizeName(s string) string {
+	return strings.Join(nameTokens(s), "-")
+}
+
+// similarity returns 1 minus the normalized Levenshtein distance of a and b.
+func similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
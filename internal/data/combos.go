@@ -0,0 +1,131 @@
+package data
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TriggerCombo is the recorded outcome for workflows using exactly this set
+// of triggers.
+type TriggerCombo struct {
+	Combo          string  `json:"combo"`
+	SuccessRate    float64 `json:"success_rate"`
+	Count          int     `json:"count"`
+	Risk           string  `json:"risk"`
+	Recommendation string  `json:"recommendation"`
+}
+
+// Triggers returns the trigger names making up the combo.
+func (c TriggerCombo) Triggers() []string {
+	return strings.Split(c.Combo, "+")
+}
+
+// ComboMatch is the known combo closest to a trigger selection.
+type ComboMatch struct {
+	TriggerCombo
+	// Exact is true when the combo has exactly the selected triggers.
+	Exact bool
+}
+
+// ComboSuggestion is a single-trigger change that improves the expected
+// success rate of a selection.
+type ComboSuggestion struct {
+	Trigger     string
+	Add         bool
+	SuccessRate float64
+}
+
+// ComboKey returns the canonical combo string for a set of triggers.
+func ComboKey(triggers []string) string {
+	sorted := slices.Clone(triggers)
+	sort.Strings(sorted)
+	return strings.Join(slices.Compact(sorted), "+")
+}
+
+// MatchCombo finds the combo recorded for exactly the given triggers, or
+// failing that the most similar one by overlap. It reports false when no
+// combo shares any trigger with the selection.
+func (p *Patterns) MatchCombo(triggers []string) (ComboMatch, bool) {
+	if len(triggers) == 0 {
+		return ComboMatch{}, false
+	}
+
+	key := ComboKey(triggers)
+	for _, c := range p.TriggerCombos {
+		if c.Combo == key {
+			return ComboMatch{TriggerCombo: c, Exact: true}, true
+		}
+	}
+
+	var best ComboMatch
+	bestScore := 0.0
+	for _, c := range p.TriggerCombos {
+		score := jaccard(triggers, c.Triggers())
+		if score > bestScore || (score == bestScore && score > 0 && c.Count > best.Count) {
+			best = ComboMatch{TriggerCombo: c}
+			bestScore = score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// SuggestCombos returns single-trigger additions or removals that land on a
+// known combo with a higher success rate than the current selection, best
+// first. A selection without a recorded combo is rated like the closest
+// one, as the triggers step shows it.
+func (p *Patterns) SuggestCombos(triggers []string) []ComboSuggestion {
+	current := 0.0
+	if m, ok := p.MatchCombo(triggers); ok {
+		current = m.SuccessRate
+	}
+
+	byKey := make(map[string]TriggerCombo, len(p.TriggerCombos))
+	var candidates []string
+	for _, c := range p.TriggerCombos {
+		byKey[c.Combo] = c
+		for _, t := range c.Triggers() {
+			if !slices.Contains(candidates, t) {
+				candidates = append(candidates, t)
+			}
+		}
+	}
+	for _, t := range triggers {
+		if !slices.Contains(candidates, t) {
+			candidates = append(candidates, t)
+		}
+	}
+
+	var out []ComboSuggestion
+	for _, t := range candidates {
+		selected := slices.Contains(triggers, t)
+		var next []string
+		if selected {
+			next = slices.DeleteFunc(slices.Clone(triggers), func(s string) bool { return s == t })
+		} else {
+			next = append(slices.Clone(triggers), t)
+		}
+		c, ok := byKey[ComboKey(next)]
+		if !ok || c.SuccessRate <= current {
+			continue
+		}
+		out = append(out, ComboSuggestion{Trigger: t, Add: !selected, SuccessRate: c.SuccessRate})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].SuccessRate > out[j].SuccessRate })
+	return out
+}
+
+func jaccard(a, b []string) float64 {
+	inter := 0
+	for _, x := range a {
+		if slices.Contains(b, x) {
+			inter++
+		}
+	}
+	union := len(a) + len(b) - inter
+	if union == 0 {
+		return 0
+	}
+	return float64(inter) / float64(union)
+}
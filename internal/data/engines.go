@@ -0,0 +1,34 @@
+package data
+
+// Engine is an AI engine gh-aw can run the agent with.
+type Engine struct {
+	ID          string
+	Label       string
+	Description string
+	// Secret is the repository secret the engine authenticates with.
+	Secret string
+	// MaxTurns is true when the engine supports limiting chat iterations.
+	MaxTurns bool
+	// Custom engines run user-provided steps instead of a built-in agent.
+	Custom bool
+}
+
+// DefaultEngine is the engine gh-aw uses when none is specified.
+const DefaultEngine = "copilot"
+
+var Engines = []Engine{
+	{ID: "copilot", Label: "Copilot", Description: "GitHub Copilot CLI", Secret: "COPILOT_GITHUB_TOKEN"},
+	{ID: "claude", Label: "Claude", Description: "Anthropic Claude Code", Secret: "ANTHROPIC_API_KEY", MaxTurns: true},
+	{ID: "codex", Label: "Codex", Description: "OpenAI Codex CLI", Secret: "OPENAI_API_KEY"},
+	{ID: "custom", Label: "Custom", Description: "Run your own agent command", Custom: true},
+}
+
+// FindEngine returns the engine with the given ID.
+func FindEngine(id string) (Engine, bool) {
+	for _, e := range Engines {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Engine{}, false
+}
@@ -0,0 +1,65 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// MCPServer is an MCP server the agent can use, run either as a stdio
+// command or reached over HTTP.
+type MCPServer struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Command     string            `json:"command,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	Allowed     []string          `json:"allowed"`
+}
+
+var (
+	mcpNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	envKeyRe  = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Validate checks that the server has a usable name, exactly one of a
+// command or URL, and a non-empty list of allowed tools.
+func (s MCPServer) Validate() error {
+	if !mcpNameRe.MatchString(s.Name) {
+		return fmt.Errorf("server name %q must be lowercase letters, digits, '-' or '_'", s.Name)
+	}
+	switch {
+	case s.Command == "" && s.URL == "":
+		return fmt.Errorf("%s: needs a command or a URL", s.Name)
+	case s.Command != "" && s.URL != "":
+		return fmt.Errorf("%s: use either a command or a URL, not both", s.Name)
+	case s.URL != "":
+		u, err := url.Parse(s.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%s: %q is not an http(s) URL", s.Name, s.URL)
+		}
+	}
+	for k := range s.Env {
+		if !envKeyRe.MatchString(k) {
+			return fmt.Errorf("%s: %q is not a valid environment variable name", s.Name, k)
+		}
+	}
+	if len(s.Allowed) == 0 {
+		return fmt.Errorf("%s: list the tools the agent may call", s.Name)
+	}
+	return nil
+}
+
+func loadMCPCatalog() ([]MCPServer, error) {
+	data, err := patternsFS.ReadFile("mcp_servers.json")
+	if err != nil {
+		return nil, err
+	}
+	var servers []MCPServer
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, fmt.Errorf("mcp_servers.json: %w", err)
+	}
+	return servers, nil
+}
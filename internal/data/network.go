@@ -0,0 +1,99 @@
+package data
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/ecosystem"
+)
+
+// NetworkPreset is a group of domains the agent can be allowed to reach,
+// named by its gh-aw ecosystem identifier.
+type NetworkPreset struct {
+	ID      string
+	Label   string
+	Domains []string
+}
+
+var NetworkPresets = []NetworkPreset{
+	{
+		ID:      "go",
+		Label:   "Go module proxy",
+		Domains: []string{"proxy.golang.org", "sum.golang.org"},
+	},
+	{
+		ID:      "node",
+		Label:   "npm",
+		Domains: []string{"registry.npmjs.org"},
+	},
+	{
+		ID:      "python",
+		Label:   "PyPI",
+		Domains: []string{"pypi.org", "files.pythonhosted.org"},
+	},
+	{
+		ID:      "rust",
+		Label:   "crates.io",
+		Domains: []string{"crates.io", "index.crates.io", "static.crates.io"},
+	},
+	{
+		ID:      "java",
+		Label:   "Maven Central",
+		Domains: []string{"repo.maven.apache.org", "repo1.maven.org", "plugins.gradle.org"},
+	},
+	{
+		ID:      "ruby",
+		Label:   "RubyGems",
+		Domains: []string{"rubygems.org", "index.rubygems.org"},
+	},
+	{
+		ID:      "github",
+		Label:   "GitHub",
+		Domains: []string{"github.com", "api.github.com", "raw.githubusercontent.com"},
+	},
+}
+
+// FindNetworkPreset returns the preset with the given ID.
+func FindNetworkPreset(id string) (NetworkPreset, bool) {
+	for _, p := range NetworkPresets {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return NetworkPreset{}, false
+}
+
+// NetworkPresetsFor returns the IDs of the presets the ecosystems' registries
+// belong to, in preset order.
+func NetworkPresetsFor(ecosystems []ecosystem.Ecosystem) []string {
+	var ids []string
+	for _, p := range NetworkPresets {
+		if slices.ContainsFunc(ecosystems, func(e ecosystem.Ecosystem) bool { return e.Network == p.ID }) {
+			ids = append(ids, p.ID)
+		}
+	}
+	return ids
+}
+
+// DetectNetworkPresets returns the IDs of presets for the ecosystems
+// detected at the root of fsys.
+func DetectNetworkPresets(fsys fs.FS) []string {
+	return NetworkPresetsFor(ecosystem.Detect(fsys))
+}
+
+var domainRe = regexp.MustCompile(`^(\*\.)?([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// ValidateDomain checks that d is a hostname, optionally with a leading
+// wildcard label, without scheme, port or path.
+func ValidateDomain(d string) error {
+	if strings.Contains(d, "://") || strings.ContainsAny(d, "/:") {
+		return fmt.Errorf("%q: enter a bare domain without scheme, port or path", d)
+	}
+	if len(d) > 253 || !domainRe.MatchString(strings.ToLower(d)) {
+		return fmt.Errorf("%q is not a valid domain", d)
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package data
 import (
 	"embed"
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
-//go:embed patterns.json
+//go:embed patterns.json mcp_servers.json
 var patternsFS embed.FS
 
 type TriggerConfig struct {
@@ -13,40 +15,68 @@ type TriggerConfig struct {
 	Config map[string]string `json:"config"`
 }
 
+// TopRepo is a well-known repository using an archetype.
+type TopRepo struct {
+	Repo  string `json:"repo"`
+	Stars int    `json:"stars"`
+}
+
 type Archetype struct {
-	ID                    string          `json:"id"`
-	Label                 string          `json:"label"`
-	Description           string          `json:"description"`
-	RecommendedTriggers   []TriggerConfig `json:"recommended_triggers"`
-	RecommendedSafeOutputs []string       `json:"recommended_safe_outputs"`
-	TimeoutMinutes        int             `json:"timeout_minutes"`
-	Tips                  []string        `json:"tips"`
+	ID                     string          `json:"id"`
+	Label                  string          `json:"label"`
+	Description            string          `json:"description"`
+	SuccessRate            float64         `json:"success_rate"`
+	Count                  int             `json:"count"`
+	RecommendedTriggers    []TriggerConfig `json:"recommended_triggers"`
+	RecommendedSafeOutputs []string        `json:"recommended_safe_outputs"`
+	RecommendedTools       []string        `json:"recommended_tools"`
+	TimeoutMinutes         int             `json:"timeout_minutes"`
+	PromptStyle            string          `json:"prompt_style"`
+	SizeRangeBytes         []int           `json:"size_range_bytes"`
+	TopRepos               []TopRepo       `json:"top_repos"`
+	Tips                   []string        `json:"tips"`
+	AntiPatterns           []string        `json:"anti_patterns"`
 }
 
 type Patterns struct {
-	Archetypes []Archetype `json:"archetypes"`
+	Archetypes       []Archetype       `json:"archetypes"`
+	TriggerCombos    []TriggerCombo    `json:"trigger_combos"`
+	Triggers         []TriggerSpec     `json:"triggers"`
+	ResearchFindings map[string]string `json:"research_findings"`
+	ConfigDefaults   ConfigDefaults    `json:"config_defaults"`
+	AntiPatterns     []AntiPattern     `json:"anti_patterns"`
+
+	// MCPCatalog lists common MCP servers, loaded from mcp_servers.json.
+	MCPCatalog []MCPServer `json:"-"`
+}
+
+// FindArchetype returns the archetype with the given ID.
+func (p *Patterns) FindArchetype(id string) (Archetype, bool) {
+	for _, a := range p.Archetypes {
+		if a.ID == id {
+			return a, true
+		}
+	}
+	return Archetype{}, false
 }
 
-var AllTriggers = []string{
-	"issues",
-	"pull_request",
-	"push",
-	"schedule",
-	"workflow_dispatch",
-	"issue_comment",
-	"discussion",
-	"release",
+// LookupArchetype is FindArchetype for IDs the user typed: an unknown ID
+// is an error listing the known ones.
+func (p *Patterns) LookupArchetype(id string) (Archetype, error) {
+	if a, ok := p.FindArchetype(id); ok {
+		return a, nil
+	}
+	var ids []string
+	for _, a := range p.Archetypes {
+		ids = append(ids, a.ID)
+	}
+	return Archetype{}, fmt.Errorf("unknown archetype %q (available: %s)", id, strings.Join(ids, ", "))
 }
 
-var TriggerDescriptions = map[string]string{
-	"issues":            "When issues are opened or edited",
-	"pull_request":      "When PRs are opened or updated",
-	"push":              "When code is pushed to a branch",
-	"schedule":          "Run on a cron schedule",
-	"workflow_dispatch": "Manual trigger from Actions tab",
-	"issue_comment":     "When issue comments are posted",
-	"discussion":        "When discussions are created",
-	"release":           "When releases are published",
+// WorkflowName is the file name, without .md, a workflow for the
+// archetype gets unless the user names it.
+func (a Archetype) WorkflowName() string {
+	return strings.ReplaceAll(strings.ToLower(a.Label), " ", "-")
 }
 
 func LoadPatterns() (*Patterns, error) {
@@ -58,6 +88,9 @@ func LoadPatterns() (*Patterns, error) {
 	if err := json.Unmarshal(data, &p); err != nil {
 		return nil, err
 	}
+	if p.MCPCatalog, err = loadMCPCatalog(); err != nil {
+		return nil, err
+	}
 	return &p, nil
 }
 
@@ -79,3 +112,23 @@ func ArchetypeEmoji(id string) string {
 	}
 	return "📋"
 }
+
+// ArchetypeASCII returns a plain-text tag standing in for the archetype's
+// emoji on terminals that can't show it.
+func ArchetypeASCII(id string) string {
+	m := map[string]string{
+		"issue-triage":          "[tri]",
+		"code-improvement":      "[fix]",
+		"status-report":         "[rpt]",
+		"upstream-monitor":      "[ups]",
+		"dependency-monitor":    "[dep]",
+		"pr-review":             "[rev]",
+		"documentation-updater": "[doc]",
+		"content-moderation":    "[mod]",
+		"custom":                "[cus]",
+	}
+	if t, ok := m[id]; ok {
+		return t
+	}
+	return "[wf]"
+}
@@ -0,0 +1,140 @@
+package data
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pre-step parameter kinds.
+const (
+	ParamNumber = "number"
+	ParamText   = "text"
+)
+
+// PreStepParam is a tweakable value substituted into a recipe's command
+// as {key}.
+type PreStepParam struct {
+	Key     string
+	Label   string
+	Kind    string
+	Default string
+}
+
+// PreStepRecipe is a data-gathering step that runs before the agent and
+// writes its result to Output for the prompt to reference.
+type PreStepRecipe struct {
+	ID          string
+	Label       string
+	Description string
+	Command     string
+	Output      string
+	Params      []PreStepParam
+	// Scopes are the token scopes the command reads.
+	Scopes []string
+}
+
+// PreStepRecipes is the library of data-gathering recipes, in display
+// order.
+var PreStepRecipes = []PreStepRecipe{
+	{
+		ID:          "open-issues",
+		Label:       "Open issues",
+		Description: "current open issues",
+		Command:     "gh issue list --state open --limit {limit} --json number,title,labels,assignees,createdAt > open_issues.json",
+		Output:      "open_issues.json",
+		Params:      []PreStepParam{{Key: "limit", Label: "Max issues", Kind: ParamNumber, Default: "100"}},
+		Scopes:      []string{"issues"},
+	},
+	{
+		ID:          "open-prs",
+		Label:       "Open pull requests",
+		Description: "current open pull requests",
+		Command:     "gh pr list --state open --limit {limit} --json number,title,author,createdAt > open_prs.json",
+		Output:      "open_prs.json",
+		Params:      []PreStepParam{{Key: "limit", Label: "Max PRs", Kind: ParamNumber, Default: "100"}},
+		Scopes:      []string{"pull-requests"},
+	},
+	{
+		ID:          "recent-commits",
+		Label:       "Recent commits",
+		Description: "the most recent commits on the default branch",
+		Command:     "git log --oneline -{count} > recent_commits.txt",
+		Output:      "recent_commits.txt",
+		Params:      []PreStepParam{{Key: "count", Label: "Commits", Kind: ParamNumber, Default: "20"}},
+		Scopes:      []string{"contents"},
+	},
+	{
+		ID:          "failed-runs",
+		Label:       "Failed workflow runs",
+		Description: "recent failed workflow runs",
+		Command:     "gh run list --status failure --limit {limit} --json databaseId,workflowName,headBranch,createdAt,url > failed_runs.json",
+		Output:      "failed_runs.json",
+		Params:      []PreStepParam{{Key: "limit", Label: "Max runs", Kind: ParamNumber, Default: "20"}},
+		Scopes:      []string{"actions"},
+	},
+	{
+		ID:          "dependencies",
+		Label:       "Dependency snapshot",
+		Description: "the contents of the dependency manifests",
+		Command:     "cat {files} 2>/dev/null > deps_snapshot.txt || true",
+		Output:      "deps_snapshot.txt",
+		Params:      []PreStepParam{{Key: "files", Label: "Manifests", Kind: ParamText, Default: "package.json go.mod requirements.txt"}},
+		Scopes:      []string{"contents"},
+	},
+	{
+		ID:          "releases",
+		Label:       "Releases",
+		Description: "the latest releases",
+		Command:     "gh release list --limit {limit} --json tagName,name,publishedAt,isPrerelease > releases.json",
+		Output:      "releases.json",
+		Params:      []PreStepParam{{Key: "limit", Label: "Max releases", Kind: ParamNumber, Default: "10"}},
+		Scopes:      []string{"contents"},
+	},
+}
+
+// FindPreStepRecipe returns the recipe with the given ID.
+func FindPreStepRecipe(id string) (PreStepRecipe, bool) {
+	for _, r := range PreStepRecipes {
+		if r.ID == id {
+			return r, true
+		}
+	}
+	return PreStepRecipe{}, false
+}
+
+// Value returns the parameter's value from values, or its default.
+func (p PreStepParam) Value(values map[string]string) string {
+	if v := strings.TrimSpace(values[p.Key]); v != "" {
+		return v
+	}
+	return p.Default
+}
+
+// Validate checks parameter values against their kinds. Missing values
+// fall back to the defaults.
+func (r PreStepRecipe) Validate(values map[string]string) error {
+	for _, p := range r.Params {
+		v := p.Value(values)
+		switch p.Kind {
+		case ParamNumber:
+			if n, err := strconv.Atoi(v); err != nil || n <= 0 {
+				return fmt.Errorf("%s: %s must be a positive number", r.Label, strings.ToLower(p.Label))
+			}
+		case ParamText:
+			if strings.ContainsAny(v, "\n;&|`$<>") {
+				return fmt.Errorf("%s: %s must not contain shell operators", r.Label, strings.ToLower(p.Label))
+			}
+		}
+	}
+	return nil
+}
+
+// Script returns the recipe's command with parameters substituted.
+func (r PreStepRecipe) Script(values map[string]string) string {
+	var pairs []string
+	for _, p := range r.Params {
+		pairs = append(pairs, "{"+p.Key+"}", p.Value(values))
+	}
+	return strings.NewReplacer(pairs...).Replace(r.Command)
+}
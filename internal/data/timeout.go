@@ -0,0 +1,72 @@
+package data
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultTimeoutMinutes is used when neither the triggers nor the archetype
+// recommend a timeout.
+const DefaultTimeoutMinutes = 30
+
+// ConfigDefaults are research-backed defaults for workflow settings.
+type ConfigDefaults struct {
+	Model               *string        `json:"model"`
+	TimeoutByTrigger    map[string]int `json:"timeout_by_trigger"`
+	PromptSizeSweetSpot []int          `json:"prompt_size_sweet_spot"`
+}
+
+// Timeout is a resolved timeout along with an explanation of where the value
+// came from.
+type Timeout struct {
+	Minutes int
+	Source  string
+}
+
+// TimeoutBand returns the range of recommended timeouts across the given
+// trigger events. It reports false if none of them has a recommendation.
+func (p *Patterns) TimeoutBand(events []string) (lo, hi int, ok bool) {
+	for _, e := range events {
+		t, found := p.ConfigDefaults.TimeoutByTrigger[e]
+		if !found {
+			continue
+		}
+		if !ok || t < lo {
+			lo = t
+		}
+		if !ok || t > hi {
+			hi = t
+		}
+		ok = true
+	}
+	return lo, hi, ok
+}
+
+// ResolveTimeout picks the timeout for a workflow. A positive override wins;
+// otherwise the longest per-trigger recommendation is used so every trigger
+// has enough time, then the archetype's default, then DefaultTimeoutMinutes.
+func (p *Patterns) ResolveTimeout(a Archetype, events []string, override int) Timeout {
+	if override > 0 {
+		return Timeout{Minutes: override, Source: "set manually"}
+	}
+
+	if _, hi, ok := p.TimeoutBand(events); ok {
+		var from []string
+		for _, e := range events {
+			if t, found := p.ConfigDefaults.TimeoutByTrigger[e]; found && t == hi {
+				from = append(from, e)
+			}
+		}
+		sort.Strings(from)
+		return Timeout{
+			Minutes: hi,
+			Source:  fmt.Sprintf("recommended for %s triggers", strings.Join(from, ", ")),
+		}
+	}
+
+	if a.TimeoutMinutes > 0 {
+		return Timeout{Minutes: a.TimeoutMinutes, Source: fmt.Sprintf("%s archetype default", a.Label)}
+	}
+	return Timeout{Minutes: DefaultTimeoutMinutes, Source: "default"}
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// TipStep is the wizard step a tip is relevant to.
+type TipStep int
+
+const (
+	TipGeneral TipStep = iota
+	TipTriggers
+	TipContext
+	TipPreSteps
+	TipNetwork
+	TipPreview
+)
+
+// TipState is the subset of the user's choices that tips are checked against.
+type TipState struct {
+	Triggers    []string
+	PromptBytes int
+	HasPreSteps bool
+	HasDoNot    bool
+	HasNetwork  bool
+}
+
+// TipRule ties a tip to the step it concerns and, when the tip can be checked,
+// the condition under which the current choices contradict it.
+type TipRule struct {
+	Tip         string
+	Step        TipStep
+	Contradicts func(TipState) bool
+}
+
+var (
+	tipComboRe = regexp.MustCompile(`\b([a-z_]+(?:\+[a-z_]+)+)\b`)
+	tipSizeRe  = regexp.MustCompile(`(\d+)-(\d+)\s*KB`)
+)
+
+// ClassifyTip maps free-form tip text from patterns.json to a TipRule. Tips
+// that don't match a known shape are returned as general, uncheckable rules.
+func ClassifyTip(tip string) TipRule {
+	r := TipRule{Tip: tip, Step: TipGeneral}
+
+	switch {
+	case tipComboRe.MatchString(tip):
+		required := strings.Split(tipComboRe.FindStringSubmatch(tip)[1], "+")
+		r.Step = TipTriggers
+		r.Contradicts = func(s TipState) bool { return !containsAll(s.Triggers, required) }
+
+	case strings.Contains(tip, "workflow_dispatch"):
+		r.Step = TipTriggers
+		r.Contradicts = func(s TipState) bool { return !slices.Contains(s.Triggers, "workflow_dispatch") }
+
+	case tipSizeRe.MatchString(tip):
+		m := tipSizeRe.FindStringSubmatch(tip)
+		lo, _ := strconv.Atoi(m[1])
+		hi, _ := strconv.Atoi(m[2])
+		r.Step = TipContext
+		r.Contradicts = func(s TipState) bool {
+			return s.PromptBytes < lo*1000 || s.PromptBytes > hi*1000
+		}
+
+	case strings.Contains(strings.ToLower(tip), "network access"):
+		r.Step = TipNetwork
+		r.Contradicts = func(s TipState) bool { return !s.HasNetwork }
+
+	case containsAny(strings.ToLower(tip), "pre-steps", "pre-fetch", "steps:"):
+		r.Step = TipPreSteps
+		r.Contradicts = func(s TipState) bool { return !s.HasPreSteps }
+
+	case strings.Contains(tip, "DO NOT"):
+		r.Step = TipPreview
+		r.Contradicts = func(s TipState) bool { return !s.HasDoNot }
+	}
+
+	return r
+}
+
+// NeedsNetwork reports whether the archetype's tips call for network access.
+func (a Archetype) NeedsNetwork() bool {
+	for _, r := range a.TipRules() {
+		if r.Step == TipNetwork {
+			return true
+		}
+	}
+	return false
+}
+
+// TipRules classifies all of the archetype's tips.
+func (a Archetype) TipRules() []TipRule {
+	rules := make([]TipRule, 0, len(a.Tips))
+	for _, t := range a.Tips {
+		rules = append(rules, ClassifyTip(t))
+	}
+	return rules
+}
+
+func containsAll(have, want []string) bool {
+	for _, w := range want {
+		if !slices.Contains(have, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
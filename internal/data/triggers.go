@@ -0,0 +1,91 @@
+package data
+
+// Trigger shapes control how a trigger's config renders under `on:`.
+const (
+	// ShapeMap renders fields as keys under the trigger (the default).
+	ShapeMap = "map"
+	// ShapeSequence renders fields as a single list item under the trigger.
+	ShapeSequence = "sequence"
+	// ShapeScalar renders the trigger's single field value inline.
+	ShapeScalar = "scalar"
+)
+
+// Trigger field kinds.
+const (
+	FieldString = "string"
+	FieldList   = "list"
+	FieldCron   = "cron"
+)
+
+// Risk levels for triggers.
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// TriggerField describes one configurable key of a trigger. String and cron
+// fields use Value; list fields use Values.
+type TriggerField struct {
+	Key    string   `json:"key"`
+	Kind   string   `json:"kind"`
+	Value  string   `json:"value,omitempty"`
+	Values []string `json:"values,omitempty"`
+}
+
+// TriggerSpec is a catalog entry for something that can go under `on:`.
+type TriggerSpec struct {
+	ID          string `json:"id"`
+	Event       string `json:"event"`
+	Description string `json:"description"`
+	Risk        string `json:"risk"`
+	// Finding is the research_findings key explaining the risk, if any.
+	Finding string `json:"finding"`
+	// Modifier entries (reaction, stop-after) adjust how the workflow
+	// triggers rather than being events themselves.
+	Modifier bool           `json:"modifier"`
+	Shape    string         `json:"shape"`
+	Config   []TriggerField `json:"config"`
+}
+
+// EventName returns the key the trigger renders under. Several catalog
+// entries may share one event, e.g. issues and issues_labeled.
+func (t TriggerSpec) EventName() string {
+	if t.Event != "" {
+		return t.Event
+	}
+	return t.ID
+}
+
+// Branches returns the branches the trigger is limited to, or nil when it
+// runs on every branch.
+func (t TriggerSpec) Branches() []string {
+	for _, f := range t.Config {
+		if f.Key == "branches" {
+			return f.Values
+		}
+	}
+	return nil
+}
+
+// Trigger returns the catalog entry with the given ID.
+func (p *Patterns) Trigger(id string) (TriggerSpec, bool) {
+	for _, t := range p.Triggers {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	return TriggerSpec{}, false
+}
+
+// TriggerEvents returns the event names of the given triggers, skipping
+// modifiers. This is the form trigger combos and timeouts are keyed by.
+func TriggerEvents(triggers []TriggerSpec) []string {
+	var events []string
+	for _, t := range triggers {
+		if !t.Modifier {
+			events = append(events, t.EventName())
+		}
+	}
+	return events
+}
@@ -0,0 +1,155 @@
+// Package draft stores in-progress wizard sessions under the user cache
+// directory, one per repository, so an interrupted session can be resumed.
+package draft
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+)
+
+// Draft is a saved wizard session.
+type Draft struct {
+	// Repo is the absolute path of the repository the session is for.
+	Repo    string    `json:"repo"`
+	Updated time.Time `json:"updated"`
+	// Summary describes the session for listings, e.g. the archetype and
+	// step reached.
+	Summary string `json:"summary"`
+	// State is the wizard's own serialized state.
+	State json.RawMessage `json:"state"`
+}
+
+// Dir returns the directory drafts are stored in.
+func Dir() (string, error) {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cache, "gh-aw-create", "drafts"), nil
+}
+
+// RepoPath resolves dir to the path drafts are keyed by: the root of the
+// repository it is in, so a session started anywhere in the repository
+// resumes from any other directory in it.
+func RepoPath(dir string) (string, error) {
+	root, err := config.RepoRoot(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Clean(root), nil
+}
+
+// path returns the draft file for a repository. The name is a hash of the
+// path so any repository maps to a safe, fixed-length file name.
+func path(repo string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(repo))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// Save writes the draft for d.Repo, replacing any earlier one.
+func Save(d Draft) error {
+	p, err := path(d.Repo)
+	if err != nil {
+		return err
+	}
+	// Drafts can hold MCP server env values, so only the user may read
+	// them. MkdirAll leaves an existing directory's mode alone, so the
+	// chmod applies it either way.
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	if err := os.Chmod(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so an interrupted save never leaves a truncated
+	// draft behind.
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// Load returns the draft for a repository. ok is false when there is none.
+func Load(repo string) (d Draft, ok bool, err error) {
+	p, err := path(repo)
+	if err != nil {
+		return Draft{}, false, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Draft{}, false, nil
+	}
+	if err != nil {
+		return Draft{}, false, err
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return Draft{}, false, fmt.Errorf("%s: %w", p, err)
+	}
+	return d, true, nil
+}
+
+// Delete removes the draft for a repository. Deleting a missing draft is
+// not an error.
+func Delete(repo string) error {
+	p, err := path(repo)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List returns all saved drafts, most recently updated first. Unreadable
+// files are skipped.
+func List() ([]Draft, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var drafts []Draft
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var d Draft
+		if json.Unmarshal(data, &d) == nil && d.Repo != "" {
+			drafts = append(drafts, d)
+		}
+	}
+	sort.Slice(drafts, func(i, j int) bool { return drafts[i].Updated.After(drafts[j].Updated) })
+	return drafts, nil
+}
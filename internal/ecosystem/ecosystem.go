@@ -0,0 +1,224 @@
+// Package ecosystem detects the package ecosystems a repository uses from
+// the manifests and lockfiles at its root.
+package ecosystem
+
+import (
+	"bytes"
+	"io/fs"
+)
+
+// Ecosystem is a package manager the wizard can snapshot dependencies for.
+type Ecosystem struct {
+	ID    string
+	Label string
+	// Network is the gh-aw network ecosystem its registry belongs to.
+	Network string
+	// Script writes a JSON array of {name, current, latest} objects to
+	// Output. latest is null when the tool can't tell.
+	Script string
+	Output string
+	// OutdatedOnly is set when the tool only reports packages with a newer
+	// release.
+	OutdatedOnly bool
+
+	detect func(fsys fs.FS) bool
+}
+
+// All lists the supported ecosystems in detection order.
+var All = []Ecosystem{
+	{
+		ID:      "go",
+		Label:   "Go",
+		Network: "go",
+		Script: `go list -m -u -json all \
+  | jq -s '[.[] | select(.Main != true) | {name: .Path, current: .Version, latest: (.Update.Version // .Version)}]' \
+  > deps_go.json`,
+		Output: "deps_go.json",
+		detect: anyFile("go.mod"),
+	},
+	{
+		ID:      "pnpm",
+		Label:   "pnpm",
+		Network: "node",
+		Script:  nodeScript("deps_pnpm.json"),
+		Output:  "deps_pnpm.json",
+		detect:  allFiles("package.json", "pnpm-lock.yaml"),
+	},
+	{
+		ID:      "yarn",
+		Label:   "Yarn",
+		Network: "node",
+		Script:  nodeScript("deps_yarn.json"),
+		Output:  "deps_yarn.json",
+		// With both lockfiles pnpm wins, as it does over npm.
+		detect: func(fsys fs.FS) bool {
+			return allFiles("package.json", "yarn.lock")(fsys) && !exists(fsys, "pnpm-lock.yaml")
+		},
+	},
+	{
+		ID:      "npm",
+		Label:   "npm",
+		Network: "node",
+		Script:  nodeScript("deps_npm.json"),
+		Output:  "deps_npm.json",
+		detect: func(fsys fs.FS) bool {
+			return anyFile("package.json")(fsys) && !anyFile("pnpm-lock.yaml", "yarn.lock")(fsys)
+		},
+	},
+	{
+		ID:      "poetry",
+		Label:   "Poetry",
+		Network: "python",
+		Script: `pipx install poetry >/dev/null
+poetry install --no-root --quiet
+poetry run pip list --outdated --format=json \
+  | jq '[.[] | {name, current: .version, latest: .latest_version}]' \
+  > deps_poetry.json`,
+		Output:       "deps_poetry.json",
+		OutdatedOnly: true,
+		detect: func(fsys fs.FS) bool {
+			return anyFile("poetry.lock")(fsys) || fileContains(fsys, "pyproject.toml", "[tool.poetry]")
+		},
+	},
+	{
+		ID:      "pipenv",
+		Label:   "Pipenv",
+		Network: "python",
+		Script: `pipx install pipenv >/dev/null
+pipenv install --dev --quiet
+pipenv run pip list --outdated --format=json \
+  | jq '[.[] | {name, current: .version, latest: .latest_version}]' \
+  > deps_pipenv.json`,
+		Output:       "deps_pipenv.json",
+		OutdatedOnly: true,
+		detect:       anyFile("Pipfile"),
+	},
+	{
+		ID:      "pip",
+		Label:   "pip",
+		Network: "python",
+		Script: `if [ -f requirements.txt ]; then pip install --quiet -r requirements.txt; else pip install --quiet .; fi
+pip list --outdated --format=json \
+  | jq '[.[] | {name, current: .version, latest: .latest_version}]' \
+  > deps_pip.json`,
+		Output:       "deps_pip.json",
+		OutdatedOnly: true,
+		detect: func(fsys fs.FS) bool {
+			if anyFile("poetry.lock", "Pipfile")(fsys) {
+				return false
+			}
+			return anyFile("requirements.txt", "setup.py")(fsys) ||
+				(exists(fsys, "pyproject.toml") && !fileContains(fsys, "pyproject.toml", "[tool.poetry]"))
+		},
+	},
+	{
+		ID:      "cargo",
+		Label:   "Cargo",
+		Network: "rust",
+		Script: `cargo metadata --format-version 1 \
+  | jq '[.packages[] | select(.source != null) | {name, current: .version, latest: null}]' \
+  > deps_cargo.json`,
+		Output: "deps_cargo.json",
+		detect: anyFile("Cargo.toml"),
+	},
+	{
+		ID:      "maven",
+		Label:   "Maven",
+		Network: "java",
+		Script: `mvn -q -B versions:display-dependency-updates -Dversions.outputFile=maven-updates.txt
+grep -- '->' maven-updates.txt \
+  | jq -R -s 'split("\n") | map(select(length > 0) | capture("(?<name>[^ ]+:[^ ]+)[ .]+(?<current>[^ ]+) -> (?<latest>[^ ]+)"))' \
+  > deps_maven.json`,
+		Output:       "deps_maven.json",
+		OutdatedOnly: true,
+		detect:       anyFile("pom.xml"),
+	},
+	{
+		ID:      "gradle",
+		Label:   "Gradle",
+		Network: "java",
+		Script: `./gradlew -q dependencies --configuration runtimeClasspath \
+  | grep -oE '[A-Za-z0-9_.-]+:[A-Za-z0-9_.-]+:[0-9][^ ]*' | sort -u \
+  | jq -R -s 'split("\n") | map(select(length > 0) | split(":") | {name: (.[0] + ":" + .[1]), current: .[2], latest: null})' \
+  > deps_gradle.json`,
+		Output: "deps_gradle.json",
+		detect: anyFile("build.gradle", "build.gradle.kts"),
+	},
+	{
+		ID:      "bundler",
+		Label:   "Bundler",
+		Network: "ruby",
+		Script: `bundle install --quiet
+bundle outdated --parseable \
+  | jq -R -s 'split("\n") | map(select(length > 0) | capture("^(?<name>[^ ]+) \\(newest (?<latest>[^,]+), installed (?<current>[^,)]+)"))' \
+  > deps_bundler.json`,
+		Output:       "deps_bundler.json",
+		OutdatedOnly: true,
+		detect:       anyFile("Gemfile"),
+	},
+}
+
+// nodeScript lists direct dependencies with the latest versions reported
+// by npm-check-updates, which reads package.json without installing.
+func nodeScript(output string) string {
+	return `npx --yes npm-check-updates --jsonAll > ncu.json
+jq -n --slurpfile cur package.json --slurpfile next ncu.json \
+  '($cur[0].dependencies // {}) + ($cur[0].devDependencies // {}) as $c
+   | (($next[0].dependencies // {}) + ($next[0].devDependencies // {})) as $n
+   | [$c | to_entries[] | {name: .key, current: .value, latest: $n[.key]}]' \
+  > ` + output
+}
+
+// Detect returns the ecosystems whose manifests are at the root of fsys,
+// in the order of All.
+func Detect(fsys fs.FS) []Ecosystem {
+	var found []Ecosystem
+	for _, e := range All {
+		if e.detect(fsys) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// Find returns the ecosystem with the given ID.
+func Find(id string) (Ecosystem, bool) {
+	for _, e := range All {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return Ecosystem{}, false
+}
+
+func exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
+
+func anyFile(names ...string) func(fs.FS) bool {
+	return func(fsys fs.FS) bool {
+		for _, n := range names {
+			if exists(fsys, n) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func allFiles(names ...string) func(fs.FS) bool {
+	return func(fsys fs.FS) bool {
+		for _, n := range names {
+			if !exists(fsys, n) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func fileContains(fsys fs.FS, name, substr string) bool {
+	data, err := fs.ReadFile(fsys, name)
+	return err == nil && bytes.Contains(data, []byte(substr))
+}
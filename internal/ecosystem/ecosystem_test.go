@@ -0,0 +1,73 @@
+package ecosystem
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func ids(ecosystems []Ecosystem) []string {
+	var ids []string
+	for _, e := range ecosystems {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []string
+	}{
+		{"go", []string{"go"}},
+		{"npm", []string{"npm"}},
+		{"npm-no-lockfile", []string{"npm"}},
+		{"yarn", []string{"yarn"}},
+		{"pnpm", []string{"pnpm"}},
+		// pnpm wins over yarn, and both over npm.
+		{"pnpm-and-yarn", []string{"pnpm"}},
+		{"yarn-without-manifest", nil},
+		{"pip", []string{"pip"}},
+		{"pip-pyproject", []string{"pip"}},
+		{"poetry", []string{"poetry"}},
+		// A poetry.lock makes it a Poetry project even with a PEP 621
+		// pyproject.toml.
+		{"poetry-lock", []string{"poetry"}},
+		{"pipenv", []string{"pipenv"}},
+		// A Pipfile makes it a Pipenv project even with a requirements.txt.
+		{"pipenv-requirements", []string{"pipenv"}},
+		{"cargo", []string{"cargo"}},
+		{"maven", []string{"maven"}},
+		{"gradle", []string{"gradle"}},
+		// Maven and Gradle are separate builds, so both are snapshotted.
+		{"maven-and-gradle", []string{"maven", "gradle"}},
+		{"bundler", []string{"bundler"}},
+		{"polyglot", []string{"go", "yarn", "pip"}},
+		// Only manifests at the root count.
+		{"nested", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := ids(Detect(os.DirFS(filepath.Join("testdata", tt.fixture))))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Detect(testdata/%s) = %q, want %q", tt.fixture, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	for _, e := range All {
+		got, ok := Find(e.ID)
+		if !ok || got.ID != e.ID {
+			t.Errorf("Find(%q) = %q, %v", e.ID, got.ID, ok)
+		}
+		if e.Output == "" || e.Script == "" || e.Network == "" {
+			t.Errorf("%s: Output, Script and Network must be set", e.ID)
+		}
+	}
+	if _, ok := Find("cobol"); ok {
+		t.Error("Find(cobol) found an ecosystem")
+	}
+}
@@ -0,0 +1 @@
+plugins { java }
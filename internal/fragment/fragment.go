@@ -0,0 +1,97 @@
+// Package fragment finds shared prompt fragments: markdown files, such as
+// common DO NOT rules or project context, that workflows pull in through
+// gh-aw's imports: instead of repeating them.
+package fragment
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Dir is where the wizard looks for fragments and saves new ones, relative
+// to the repository root.
+const Dir = ".github/agents/shared"
+
+// ContextFile is the fragment the wizard saves the project context to.
+const ContextFile = Dir + "/project-context.md"
+
+// Fragment is a shared markdown file a workflow can import.
+type Fragment struct {
+	// Path is relative to the repository root, with forward slashes.
+	Path string
+	// Title is the first heading, or the file name without it.
+	Title   string
+	Content string
+}
+
+// Discover lists the fragments in Dir and in the workflow directory's
+// shared/ folder, sorted by path. Missing directories are skipped.
+func Discover(fsys fs.FS, workflowDir string) ([]Fragment, error) {
+	var fragments []Fragment
+	for _, dir := range []string{Dir, path.Join(workflowDir, "shared")} {
+		matches, err := fs.Glob(fsys, path.Join(dir, "*.md"))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range matches {
+			if slices.ContainsFunc(fragments, func(f Fragment) bool { return f.Path == p }) {
+				continue
+			}
+			content, err := fs.ReadFile(fsys, p)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			fragments = append(fragments, Fragment{Path: p, Title: title(string(content), p), Content: string(content)})
+		}
+	}
+	slices.SortFunc(fragments, func(a, b Fragment) int { return strings.Compare(a.Path, b.Path) })
+	return fragments, nil
+}
+
+// ImportPath returns the path a workflow in workflowDir imports a fragment
+// by, which gh-aw resolves relative to the workflow file. Both paths are
+// relative to the repository root, which workflowDir may be.
+func ImportPath(workflowDir, fragmentPath string) string {
+	from, to := segments(workflowDir), segments(fragmentPath)
+	common := 0
+	for common < len(from) && common < len(to)-1 && from[common] == to[common] {
+		common++
+	}
+	var parts []string
+	for range from[common:] {
+		parts = append(parts, "..")
+	}
+	return path.Join(append(parts, to[common:]...)...)
+}
+
+// segments splits a slash-separated path into its elements, with none for
+// the root itself.
+func segments(p string) []string {
+	p = path.Clean(p)
+	if p == "." {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+// title returns the fragment's first markdown heading after any
+// frontmatter, falling back to its file name.
+func title(content, p string) string {
+	if rest, ok := strings.CutPrefix(content, "---\n"); ok {
+		if _, body, ok := strings.Cut(rest, "\n---\n"); ok {
+			content = body
+		}
+	}
+	for _, line := range strings.Split(content, "\n") {
+		if h, ok := strings.CutPrefix(line, "#"); ok {
+			return strings.TrimSpace(strings.TrimLeft(h, "#"))
+		}
+	}
+	return strings.TrimSuffix(path.Base(p), ".md")
+}
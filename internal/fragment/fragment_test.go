@@ -0,0 +1,25 @@
+package fragment
+
+import "testing"
+
+func TestImportPath(t *testing.T) {
+	tests := []struct {
+		workflowDir, fragment, want string
+	}{
+		{".github/workflows", ".github/agents/shared/do-not.md", "../agents/shared/do-not.md"},
+		{".github/workflows", ".github/workflows/shared/do-not.md", "shared/do-not.md"},
+		{"./.github/workflows/", ".github/agents/shared/do-not.md", "../agents/shared/do-not.md"},
+		{".", ".github/agents/shared/do-not.md", ".github/agents/shared/do-not.md"},
+		{".", "shared/do-not.md", "shared/do-not.md"},
+		{"agents", ".github/agents/shared/do-not.md", "../.github/agents/shared/do-not.md"},
+		{"agents", "agents/shared/do-not.md", "shared/do-not.md"},
+		{"a/b/c", "a/do-not.md", "../../do-not.md"},
+		// A directory named like the fragment's folder isn't a common prefix.
+		{"shared", "shared.md", "../shared.md"},
+	}
+	for _, tt := range tests {
+		if got := ImportPath(tt.workflowDir, tt.fragment); got != tt.want {
+			t.Errorf("ImportPath(%q, %q) = %q, want %q", tt.workflowDir, tt.fragment, got, tt.want)
+		}
+	}
+}
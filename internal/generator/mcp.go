@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+// renderMCPServers renders the mcp-servers block. Each server is limited to
+// its allowed tools.
+func renderMCPServers(servers []data.MCPServer) string {
+	if len(servers) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("mcp-servers:\n")
+	for _, s := range servers {
+		b.WriteString(fmt.Sprintf("  %s:\n", s.Name))
+		if s.URL != "" {
+			b.WriteString(fmt.Sprintf("    url: %s\n", strconv.Quote(s.URL)))
+		} else {
+			b.WriteString(fmt.Sprintf("    command: %s\n", yamlString(s.Command)))
+			if len(s.Args) > 0 {
+				b.WriteString(fmt.Sprintf("    args: %s\n", quotedList(s.Args)))
+			}
+		}
+		if len(s.Env) > 0 {
+			keys := make([]string, 0, len(s.Env))
+			for k := range s.Env {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			b.WriteString("    env:\n")
+			for _, k := range keys {
+				b.WriteString(fmt.Sprintf("      %s: %s\n", k, strconv.Quote(s.Env[k])))
+			}
+		}
+		b.WriteString(fmt.Sprintf("    allowed: %s\n", quotedList(s.Allowed)))
+	}
+	return b.String()
+}
+
+func quotedList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, s := range items {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
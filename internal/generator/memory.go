@@ -0,0 +1,174 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Memory kinds. Cache memory lives in the Actions cache and expires;
+// repo memory is committed to a branch and kept indefinitely.
+const (
+	MemoryNone  = ""
+	MemoryCache = "cache-memory"
+	MemoryRepo  = "repo-memory"
+)
+
+// MemoryKinds lists the memory kinds in the order the wizard cycles
+// through them.
+var MemoryKinds = []string{MemoryNone, MemoryCache, MemoryRepo}
+
+// MaxRetentionDays is the longest the Actions cache keeps entries.
+const MaxRetentionDays = 90
+
+// MemoryConfig configures what the agent remembers between runs. The zero
+// value disables memory.
+type MemoryConfig struct {
+	Kind string
+	// Key names the cache entry, or the memory branch for repo memory.
+	// Empty uses gh-aw's default.
+	Key string
+	// RetentionDays is how long cache memory is kept; zero uses gh-aw's
+	// default. It does not apply to repo memory.
+	RetentionDays int
+}
+
+var memoryKeyRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func (m MemoryConfig) Enabled() bool { return m.Kind != MemoryNone }
+
+// Validate checks the key and retention for the memory kind.
+func (m MemoryConfig) Validate() error {
+	switch m.Kind {
+	case MemoryNone:
+		return nil
+	case MemoryCache, MemoryRepo:
+	default:
+		return fmt.Errorf("unknown memory kind %q", m.Kind)
+	}
+	if m.Key != "" && !memoryKeyRe.MatchString(m.Key) {
+		return fmt.Errorf("memory key %q may only contain letters, digits, '.', '_' and '-'", m.Key)
+	}
+	if m.RetentionDays < 0 || m.RetentionDays > MaxRetentionDays {
+		return fmt.Errorf("retention must be between 1 and %d days", MaxRetentionDays)
+	}
+	if m.RetentionDays > 0 && m.Kind == MemoryRepo {
+		return fmt.Errorf("retention only applies to cache memory")
+	}
+	return nil
+}
+
+// Dir is where the memory files are available to the agent.
+func (m MemoryConfig) Dir() string {
+	if m.Kind == MemoryRepo {
+		return "/tmp/gh-aw/repo-memory/default/"
+	}
+	return "/tmp/gh-aw/cache-memory/"
+}
+
+// renderMemory renders the memory tool entry under tools:.
+func renderMemory(m MemoryConfig) string {
+	switch m.Kind {
+	case MemoryCache:
+		if m.Key == "" && m.RetentionDays == 0 {
+			return "  cache-memory:\n"
+		}
+		var b strings.Builder
+		b.WriteString("  cache-memory:\n")
+		if m.Key != "" {
+			b.WriteString(fmt.Sprintf("    key: %s\n", m.Key))
+		}
+		if m.RetentionDays > 0 {
+			b.WriteString(fmt.Sprintf("    retention-days: %d\n", m.RetentionDays))
+		}
+		return b.String()
+	case MemoryRepo:
+		if m.Key == "" {
+			return "  repo-memory:\n"
+		}
+		return fmt.Sprintf("  repo-memory:\n    branch-name: memory/%s\n", m.Key)
+	}
+	return ""
+}
+
+// memoryRecipe is what an archetype keeps in memory: the file, what to
+// take from it at the start of a run and what to record before finishing.
+type memoryRecipe struct {
+	file  string
+	read  string
+	write string
+}
+
+var memoryRecipes = map[string]memoryRecipe{
+	"status-report": {
+		file:  "trends.json",
+		read:  "compare the previous snapshots with today's data and call out what grew, shrank or went stale since the last report",
+		write: "append a snapshot with the date, open issue and PR counts per label, and the oldest open items",
+	},
+	"issue-triage": {
+		file:  "triage-notes.md",
+		read:  "reuse the labels and duplicate groupings you chose before so similar issues are triaged consistently",
+		write: "record recurring topics, the labels you applied to them, and issue numbers that look like duplicates",
+	},
+	"pr-review": {
+		file:  "review-notes.md",
+		read:  "apply the repository conventions and recurring problems you noted before, and don't repeat feedback the authors have already declined",
+		write: "note new conventions you inferred and problems that keep coming up across pull requests",
+	},
+	"code-improvement": {
+		file:  "findings.json",
+		read:  "skip findings you have already opened a PR for or that were rejected",
+		write: "add each finding you acted on with the file, a one-line summary and the PR number",
+	},
+	"documentation-updater": {
+		file:  "docs-audit.json",
+		read:  "start with the pages that were checked longest ago",
+		write: "record each page you checked, the date, and whether it needed changes",
+	},
+	"upstream-monitor": {
+		file:  "upstream-state.json",
+		read:  "only report upstream changes newer than the versions and commits recorded there",
+		write: "store the latest version and commit you saw for each upstream",
+	},
+	"dependency-monitor": {
+		file:  "dependencies.json",
+		read:  "don't open a second PR for an update you have already proposed",
+		write: "record each dependency's current version and any update PR you opened",
+	},
+	"content-moderation": {
+		file:  "moderation-log.md",
+		read:  "use earlier decisions to keep moderation consistent and to spot repeat spam patterns",
+		write: "log each item you flagged, why, and any new spam pattern you saw",
+	},
+}
+
+var defaultMemoryRecipe = memoryRecipe{
+	file:  "notes.md",
+	read:  "pick up where the last run left off",
+	write: "record what you did and anything the next run should know",
+}
+
+// memorySection tells the agent how to use its memory, so that enabling
+// memory changes what it does rather than just adding a tool.
+func memorySection(archetype string, m MemoryConfig) string {
+	if !m.Enabled() {
+		return ""
+	}
+	r, ok := memoryRecipes[archetype]
+	if !ok {
+		r = defaultMemoryRecipe
+	}
+	path := m.Dir() + r.file
+
+	var b strings.Builder
+	b.WriteString("\n\n## Memory\n\n")
+	if m.Kind == MemoryRepo {
+		b.WriteString("Files in `" + m.Dir() + "` are committed to a memory branch and persist across runs.\n\n")
+	} else {
+		b.WriteString("Files in `" + m.Dir() + "` are cached between runs but may expire.\n\n")
+	}
+	b.WriteString(fmt.Sprintf("1. At the start of the run, read `%s` if it exists and %s\n", path, r.read))
+	b.WriteString(fmt.Sprintf("2. Before finishing, update `%s`: %s\n", path, r.write))
+	b.WriteString("3. Keep the file small — drop the oldest entries first, and never store secrets or personal data\n")
+	return b.String()
+}
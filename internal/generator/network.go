@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+// NetworkConfig controls which domains the agent can reach. The zero value
+// leaves gh-aw's defaults in place and renders nothing.
+type NetworkConfig struct {
+	// Deny drops gh-aw's default infrastructure allowlist, so only the
+	// listed ecosystems and domains are reachable.
+	Deny       bool
+	Ecosystems []string
+	Domains    []string
+}
+
+// IsZero reports whether the config leaves gh-aw's defaults unchanged.
+func (n NetworkConfig) IsZero() bool {
+	return !n.Deny && len(n.Ecosystems) == 0 && len(n.Domains) == 0
+}
+
+// AllowedDomains expands ecosystems into their domains, followed by the
+// custom domains.
+func (n NetworkConfig) AllowedDomains() []string {
+	var domains []string
+	for _, id := range n.Ecosystems {
+		if p, ok := data.FindNetworkPreset(id); ok {
+			domains = append(domains, p.Domains...)
+		}
+	}
+	return append(domains, n.Domains...)
+}
+
+func renderNetwork(n NetworkConfig) string {
+	if n.IsZero() {
+		return ""
+	}
+	if n.Deny && len(n.Ecosystems) == 0 && len(n.Domains) == 0 {
+		return "network: {}\n"
+	}
+
+	var b strings.Builder
+	b.WriteString("network:\n")
+	b.WriteString("  allowed:\n")
+	if !n.Deny {
+		b.WriteString("    - defaults\n")
+	}
+	for _, e := range n.Ecosystems {
+		b.WriteString(fmt.Sprintf("    - %s\n", e))
+	}
+	for _, d := range n.Domains {
+		b.WriteString(fmt.Sprintf("    - %q\n", d))
+	}
+	return b.String()
+}
+
+// networkSection tells the agent which domains it can reach so it doesn't
+// waste turns on calls the firewall will block.
+func networkSection(n NetworkConfig) string {
+	if n.IsZero() {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n## Network Access\n\n")
+	domains := n.AllowedDomains()
+	if len(domains) == 0 {
+		b.WriteString("You have no network access. Do not attempt network calls — work only with the repository and pre-fetched data.\n")
+		return b.String()
+	}
+
+	b.WriteString("You can only reach these domains")
+	if !n.Deny {
+		b.WriteString(" (plus GitHub and basic infrastructure)")
+	}
+	b.WriteString(":\n\n")
+	for _, d := range domains {
+		b.WriteString(fmt.Sprintf("- `%s`\n", d))
+	}
+	b.WriteString("\nRequests to any other domain will be blocked — do not attempt them.\n")
+	return b.String()
+}
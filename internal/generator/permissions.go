@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permission levels for the workflow's GITHUB_TOKEN.
+const (
+	PermissionNone  = "none"
+	PermissionRead  = "read"
+	PermissionWrite = "write"
+)
+
+// GitHubToolsets are the toolsets of the github tool, in display order.
+var GitHubToolsets = []string{"repos", "issues", "pull_requests", "actions", "code_security", "discussions"}
+
+// PermissionScopes are the token scopes the wizard lets users adjust.
+var PermissionScopes = []string{"actions", "contents", "discussions", "issues", "pull-requests", "security-events"}
+
+// toolsetScopes maps each github toolset to the scope it reads.
+var toolsetScopes = map[string]string{
+	"repos":         "contents",
+	"issues":        "issues",
+	"pull_requests": "pull-requests",
+	"actions":       "actions",
+	"code_security": "security-events",
+	"discussions":   "discussions",
+}
+
+// Toolsets returns the github toolsets for the workflow: the configured ones,
+// or the archetype's defaults when none were chosen.
+func Toolsets(cfg WorkflowConfig) []string {
+	if cfg.Toolsets != nil {
+		return cfg.Toolsets
+	}
+	if inferCapabilities(cfg.Archetype.ID).githubToolsets {
+		return GitHubToolsets
+	}
+	return nil
+}
+
+// Tools returns the names of the tools the workflow enables: the built-in
+// tools followed by any MCP servers.
+func Tools(cfg WorkflowConfig) []string {
+	tools := []string{"edit"}
+	if inferCapabilities(cfg.Archetype.ID).bash {
+		tools = append(tools, "bash")
+	}
+	if len(Toolsets(cfg)) > 0 {
+		tools = append(tools, "github")
+	}
+	if cfg.Memory.Enabled() {
+		tools = append(tools, cfg.Memory.Kind)
+	}
+	for _, s := range cfg.MCPServers {
+		tools = append(tools, s.Name)
+	}
+	return tools
+}
+
+// InferPermissions returns the least-privilege read permissions implied by
+// the workflow's tools, toolsets and pre-steps. Writes are expected to go
+// through safe-outputs, so nothing is ever inferred as write.
+func InferPermissions(cfg WorkflowConfig) map[string]string {
+	// The agent always checks out and edits the repository.
+	perms := map[string]string{"contents": PermissionRead}
+	for _, ts := range Toolsets(cfg) {
+		if scope, ok := toolsetScopes[ts]; ok {
+			perms[scope] = PermissionRead
+		}
+	}
+	for _, s := range PreSteps(cfg) {
+		for _, scope := range s.Recipe.Scopes {
+			perms[scope] = PermissionRead
+		}
+	}
+	return perms
+}
+
+// Permissions returns the configured permissions, or the inferred ones.
+func Permissions(cfg WorkflowConfig) map[string]string {
+	if cfg.Permissions != nil {
+		return cfg.Permissions
+	}
+	return InferPermissions(cfg)
+}
+
+func renderPermissions(perms map[string]string) string {
+	var scopes []string
+	for scope, level := range perms {
+		if level != "" && level != PermissionNone {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return "permissions: {}\n"
+	}
+	sort.Strings(scopes)
+
+	var b strings.Builder
+	b.WriteString("permissions:\n")
+	for _, scope := range scopes {
+		b.WriteString(fmt.Sprintf("  %s: %s\n", scope, perms[scope]))
+	}
+	return b.String()
+}
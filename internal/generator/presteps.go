@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/ecosystem"
+)
+
+// dependencySnapshot is the recipe that ecosystem-specific steps replace.
+const dependencySnapshot = "dependencies"
+
+// PreStep is a data-gathering recipe with the user's parameter values.
+type PreStep struct {
+	Recipe data.PreStepRecipe
+	// Params overrides the recipe's defaults; missing keys use them.
+	Params map[string]string
+	// Ecosystem is set for steps that snapshot one ecosystem's
+	// dependencies.
+	Ecosystem *ecosystem.Ecosystem
+}
+
+// defaultPreSteps lists the recipes each archetype gathers data with by
+// default.
+var defaultPreSteps = map[string][]string{
+	"status-report":      {"open-issues", "open-prs"},
+	"dependency-monitor": {"dependencies"},
+	"upstream-monitor":   {"recent-commits"},
+}
+
+// DefaultPreSteps returns the archetype's default pre-steps with default
+// parameters.
+func DefaultPreSteps(archetype string) []PreStep {
+	var steps []PreStep
+	for _, id := range defaultPreSteps[archetype] {
+		if r, ok := data.FindPreStepRecipe(id); ok {
+			steps = append(steps, PreStep{Recipe: r})
+		}
+	}
+	return steps
+}
+
+// PreSteps returns the configured pre-steps, or the archetype's defaults
+// when none were chosen, with the dependency snapshot expanded for the
+// detected ecosystems.
+func PreSteps(cfg WorkflowConfig) []PreStep {
+	steps := cfg.PreSteps
+	if steps == nil {
+		steps = DefaultPreSteps(cfg.Archetype.ID)
+	}
+	var expanded []PreStep
+	for _, s := range steps {
+		expanded = append(expanded, ExpandPreStep(s, cfg.Ecosystems)...)
+	}
+	return expanded
+}
+
+// ExpandPreStep replaces the generic dependency snapshot with a step per
+// ecosystem. Other steps, or the snapshot when no ecosystem was detected,
+// are returned unchanged.
+func ExpandPreStep(s PreStep, ecosystems []ecosystem.Ecosystem) []PreStep {
+	if s.Recipe.ID != dependencySnapshot || len(ecosystems) == 0 {
+		return []PreStep{s}
+	}
+	steps := make([]PreStep, 0, len(ecosystems))
+	for _, e := range ecosystems {
+		desc := e.Label + " dependencies with current and latest versions"
+		if e.OutdatedOnly {
+			desc = e.Label + " dependencies that have a newer release"
+		}
+		steps = append(steps, PreStep{
+			Recipe: data.PreStepRecipe{
+				ID:          dependencySnapshot + "-" + e.ID,
+				Label:       e.Label + " dependencies",
+				Description: desc,
+				Command:     e.Script,
+				Output:      e.Output,
+				Scopes:      s.Recipe.Scopes,
+			},
+			Ecosystem: &e,
+		})
+	}
+	return steps
+}
+
+// renderPreSteps renders one step per recipe. gh needs a token to call the
+// API, so every step gets the workflow's.
+func renderPreSteps(steps []PreStep) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("steps:\n")
+	for _, s := range steps {
+		b.WriteString(fmt.Sprintf("  - name: Fetch %s\n", strings.ToLower(s.Recipe.Label)))
+		if s.Ecosystem != nil {
+			// A failed snapshot shouldn't stop the agent; the prompt tells
+			// it how to handle a missing file.
+			b.WriteString("    continue-on-error: true\n")
+		}
+		b.WriteString("    env:\n")
+		b.WriteString("      GH_TOKEN: ${{ github.token }}\n")
+		b.WriteString("    run: |\n")
+		for _, line := range strings.Split(s.Recipe.Script(s.Params), "\n") {
+			b.WriteString(fmt.Sprintf("      %s\n", line))
+		}
+	}
+	return b.String()
+}
+
+// dataFilesSection lists the files the pre-steps produce so the agent
+// reads them instead of fetching the same data again.
+func dataFilesSection(steps []PreStep) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Pre-fetched Data\n\n")
+	b.WriteString("Use the pre-fetched data files:\n")
+	for _, s := range steps {
+		b.WriteString(fmt.Sprintf("- `%s` — %s\n", s.Recipe.Output, s.Recipe.Description))
+	}
+	return b.String()
+}
+
+// dependencyFormatSection explains the dependency snapshot format so the
+// agent can compare versions without guessing at the structure.
+func dependencyFormatSection(steps []PreStep) string {
+	var files []string
+	for _, s := range steps {
+		if s.Ecosystem != nil {
+			files = append(files, "`"+s.Recipe.Output+"`")
+		}
+	}
+	if len(files) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\n## Dependency Data Format\n\n")
+	b.WriteString(fmt.Sprintf("%s each contain a JSON array with one object per package:\n\n", strings.Join(files, ", ")))
+	b.WriteString("```json\n")
+	b.WriteString("[{\"name\": \"example\", \"current\": \"1.2.0\", \"latest\": \"1.4.1\"}]\n")
+	b.WriteString("```\n\n")
+	b.WriteString("- `current` is the version the repository uses today\n")
+	b.WriteString("- `latest` is the newest release, or `null` when it couldn't be determined — check the registry before proposing an update\n")
+	b.WriteString("- A missing or empty file means the snapshot failed; say so instead of guessing versions\n")
+	return b.String()
+}
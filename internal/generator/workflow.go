@@ -2,16 +2,94 @@ package generator
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/ecosystem"
 )
 
+// EngineConfig selects the AI engine and its engine-specific settings.
+type EngineConfig struct {
+	ID       string
+	Model    string
+	MaxTurns int
+	// Command is the agent invocation for the custom engine.
+	Command string
+}
+
+// Validate checks the settings against what the engine supports.
+func (e EngineConfig) Validate() error {
+	if e.ID == "" {
+		return nil
+	}
+	engine, ok := data.FindEngine(e.ID)
+	if !ok {
+		var ids []string
+		for _, e := range data.Engines {
+			ids = append(ids, e.ID)
+		}
+		return fmt.Errorf("unknown engine %q (available: %s)", e.ID, strings.Join(ids, ", "))
+	}
+	if strings.ContainsAny(e.Model, " \t\n") {
+		return fmt.Errorf("model %q must not contain whitespace", e.Model)
+	}
+	if e.MaxTurns < 0 {
+		return fmt.Errorf("max-turns must be positive")
+	}
+	if e.MaxTurns > 0 && !engine.MaxTurns {
+		return fmt.Errorf("max-turns is not supported by the %s engine", engine.Label)
+	}
+	if engine.Custom {
+		if e.Model != "" {
+			return fmt.Errorf("the custom engine does not take a model")
+		}
+		if strings.TrimSpace(e.Command) == "" {
+			return fmt.Errorf("the custom engine needs a command to run")
+		}
+	} else if e.Command != "" {
+		return fmt.Errorf("a command is only used with the custom engine")
+	}
+	return nil
+}
+
+var workflowNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// ValidateName checks a workflow file name, without .md.
+func ValidateName(name string) error {
+	if !workflowNameRe.MatchString(name) {
+		return fmt.Errorf("use lowercase letters, digits, '.', '_' and '-'")
+	}
+	return nil
+}
+
 type WorkflowConfig struct {
 	Archetype      data.Archetype
-	Triggers       []string
+	Triggers       []data.TriggerSpec
 	ProjectContext string
-	UseMemory      bool
+	Memory         MemoryConfig
+	// TimeoutMinutes overrides the archetype's timeout when positive.
+	TimeoutMinutes int
+	// Engine is omitted from the frontmatter when its ID is empty.
+	Engine EngineConfig
+	// Toolsets overrides the archetype's github toolsets when non-nil; an
+	// empty slice disables the github tool.
+	Toolsets []string
+	// Permissions overrides the inferred permissions when non-nil.
+	Permissions map[string]string
+	Network     NetworkConfig
+	// MCPServers are rendered into mcp-servers in the order given.
+	MCPServers []data.MCPServer
+	// PreSteps overrides the archetype's data-gathering steps when non-nil;
+	// an empty slice disables them.
+	PreSteps []PreStep
+	// Ecosystems detected in the repository replace the generic dependency
+	// snapshot with one tailored step each.
+	Ecosystems []ecosystem.Ecosystem
+	// Imports are shared fragments gh-aw merges into the workflow, as
+	// paths relative to the workflow file.
+	Imports []string
 }
 
 func Generate(cfg WorkflowConfig) string {
@@ -20,26 +98,29 @@ func Generate(cfg WorkflowConfig) string {
 	// Frontmatter
 	b.WriteString("---\n")
 	b.WriteString(fmt.Sprintf("description: \"%s\"\n", promptDescription(cfg.Archetype)))
+	if len(cfg.Imports) > 0 {
+		b.WriteString("imports:\n")
+		for _, p := range cfg.Imports {
+			b.WriteString(fmt.Sprintf("  - %s\n", yamlString(p)))
+		}
+	}
 
 	// Triggers
 	b.WriteString("on:\n")
+	seen := make(map[string]bool)
 	for _, t := range cfg.Triggers {
-		switch t {
-		case "schedule":
-			b.WriteString("  schedule:\n    - cron: '0 9 * * 1'\n")
-		case "issues":
-			b.WriteString("  issues:\n    types: [opened, edited]\n")
-		case "pull_request":
-			b.WriteString("  pull_request:\n    types: [opened, synchronize]\n")
-		case "issue_comment":
-			b.WriteString("  issue_comment:\n    types: [created]\n")
-		case "push":
-			b.WriteString("  push:\n    branches: [main]\n")
-		default:
-			b.WriteString(fmt.Sprintf("  %s:\n", t))
+		// Entries sharing an event would produce duplicate keys.
+		if seen[t.EventName()] {
+			continue
 		}
+		seen[t.EventName()] = true
+		b.WriteString(renderTrigger(t))
 	}
 
+	b.WriteString(renderPermissions(Permissions(cfg)))
+	b.WriteString(renderEngine(cfg.Engine))
+	b.WriteString(renderNetwork(cfg.Network))
+
 	// Tools
 	caps := inferCapabilities(cfg.Archetype.ID)
 	b.WriteString("tools:\n")
@@ -47,13 +128,12 @@ func Generate(cfg WorkflowConfig) string {
 	if caps.bash {
 		b.WriteString("  bash: [\":*\"]\n")
 	}
-	if caps.githubToolsets {
+	if toolsets := Toolsets(cfg); len(toolsets) > 0 {
 		b.WriteString("  github:\n")
-		b.WriteString("    toolsets: [repos, issues, pull_requests, actions, code_security, discussions]\n")
-	}
-	if cfg.UseMemory {
-		b.WriteString("  cache-memory:\n")
+		b.WriteString(fmt.Sprintf("    toolsets: [%s]\n", strings.Join(toolsets, ", ")))
 	}
+	b.WriteString(renderMemory(cfg.Memory))
+	b.WriteString(renderMCPServers(cfg.MCPServers))
 
 	// Safe outputs
 	if len(cfg.Archetype.RecommendedSafeOutputs) > 0 {
@@ -63,19 +143,16 @@ func Generate(cfg WorkflowConfig) string {
 		}
 	}
 
-	timeout := cfg.Archetype.TimeoutMinutes
+	timeout := cfg.TimeoutMinutes
 	if timeout == 0 {
-		timeout = 30
+		timeout = cfg.Archetype.TimeoutMinutes
+	}
+	if timeout == 0 {
+		timeout = data.DefaultTimeoutMinutes
 	}
 	b.WriteString(fmt.Sprintf("timeout-minutes: %d\n", timeout))
 
-	// Pre-steps
-	if caps.preSteps {
-		b.WriteString("steps:\n")
-		b.WriteString("  - name: Gather data\n")
-		b.WriteString("    run: |\n")
-		b.WriteString(preStepScript(cfg.Archetype.ID))
-	}
+	b.WriteString(renderPreSteps(PreSteps(cfg)))
 
 	b.WriteString("---\n\n")
 
@@ -85,8 +162,104 @@ func Generate(cfg WorkflowConfig) string {
 	return b.String()
 }
 
+// Stats summarizes properties of the generated workflow that guidance and
+// checks care about.
+type Stats struct {
+	PromptBytes int
+	HasPreSteps bool
+	HasDoNot    bool
+	HasNetwork  bool
+}
+
+// Analyze reports Stats for the workflow Generate would produce.
+func Analyze(cfg WorkflowConfig) Stats {
+	body := promptBody(cfg)
+	return Stats{
+		PromptBytes: len(body),
+		HasPreSteps: len(PreSteps(cfg)) > 0,
+		HasDoNot:    strings.Contains(body, "DO NOT"),
+		HasNetwork:  len(cfg.Network.AllowedDomains()) > 0,
+	}
+}
+
+// renderEngine renders the engine block, using the short form when there
+// are no settings.
+func renderEngine(e EngineConfig) string {
+	if e.ID == "" {
+		return ""
+	}
+	if e.Model == "" && e.MaxTurns == 0 && e.Command == "" {
+		return fmt.Sprintf("engine: %s\n", e.ID)
+	}
+
+	var b strings.Builder
+	b.WriteString("engine:\n")
+	b.WriteString(fmt.Sprintf("  id: %s\n", e.ID))
+	if e.Model != "" {
+		b.WriteString(fmt.Sprintf("  model: %s\n", yamlString(e.Model)))
+	}
+	if e.MaxTurns > 0 {
+		b.WriteString(fmt.Sprintf("  max-turns: %d\n", e.MaxTurns))
+	}
+	if e.Command != "" {
+		b.WriteString("  steps:\n")
+		b.WriteString("    - name: Run agent\n")
+		b.WriteString(fmt.Sprintf("      run: %s\n", strconv.Quote(e.Command)))
+	}
+	return b.String()
+}
+
+// renderTrigger renders a trigger and its config from the catalog schema.
+func renderTrigger(t data.TriggerSpec) string {
+	name := t.EventName()
+	if len(t.Config) == 0 {
+		return fmt.Sprintf("  %s:\n", name)
+	}
+	if t.Shape == data.ShapeScalar {
+		return fmt.Sprintf("  %s: %s\n", name, fieldValue(t.Config[0]))
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("  %s:\n", name))
+	for i, f := range t.Config {
+		indent := "    "
+		if t.Shape == data.ShapeSequence {
+			indent = "      "
+			if i == 0 {
+				indent = "    - "
+			}
+		}
+		b.WriteString(fmt.Sprintf("%s%s: %s\n", indent, f.Key, fieldValue(f)))
+	}
+	return b.String()
+}
+
+func fieldValue(f data.TriggerField) string {
+	switch f.Kind {
+	case data.FieldList:
+		items := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			items[i] = yamlString(v)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case data.FieldCron:
+		return "'" + f.Value + "'"
+	default:
+		return yamlString(f.Value)
+	}
+}
+
+var plainScalarRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_./-]*$`)
+
+// yamlString quotes s unless it is safe as a plain YAML scalar.
+func yamlString(s string) string {
+	if plainScalarRe.MatchString(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
 type capabilities struct {
-	preSteps       bool
 	bash           bool
 	githubToolsets bool
 }
@@ -94,9 +267,9 @@ type capabilities struct {
 func inferCapabilities(id string) capabilities {
 	switch id {
 	case "status-report":
-		return capabilities{preSteps: true, githubToolsets: true}
+		return capabilities{githubToolsets: true}
 	case "dependency-monitor", "upstream-monitor":
-		return capabilities{preSteps: true, bash: true}
+		return capabilities{bash: true}
 	case "code-improvement", "documentation-updater":
 		return capabilities{bash: true}
 	case "pr-review":
@@ -112,20 +285,6 @@ func promptDescription(a data.Archetype) string {
 	return a.Label + " — " + a.Description
 }
 
-func preStepScript(id string) string {
-	switch id {
-	case "status-report":
-		return "      gh issue list --state open --json number,title,labels,assignees,createdAt > open_issues.json\n" +
-			"      gh pr list --state open --json number,title,author,createdAt > open_prs.json\n"
-	case "dependency-monitor":
-		return "      cat package.json go.mod requirements.txt 2>/dev/null > deps_snapshot.txt\n"
-	case "upstream-monitor":
-		return "      git log --oneline -20 > recent_commits.txt\n"
-	default:
-		return "      echo 'Gathering data...'\n"
-	}
-}
-
 func promptBody(cfg WorkflowConfig) string {
 	var b strings.Builder
 	id := cfg.Archetype.ID
@@ -147,9 +306,6 @@ func promptBody(cfg WorkflowConfig) string {
 	case "status-report":
 		b.WriteString("# Status Report Generator\n\n")
 		b.WriteString("Generate a status report summarizing recent repository activity.\n\n")
-		b.WriteString("Use the pre-fetched data files:\n")
-		b.WriteString("- `open_issues.json` — current open issues\n")
-		b.WriteString("- `open_prs.json` — current open pull requests\n\n")
 		b.WriteString("## Report Format\n")
 		b.WriteString("Create an issue with:\n")
 		b.WriteString("- Summary of open issues by label/category\n")
@@ -241,12 +397,29 @@ func promptBody(cfg WorkflowConfig) string {
 		b.WriteString("- ...\n")
 	}
 
+	b.WriteString(dataFilesSection(PreSteps(cfg)))
+	b.WriteString(dependencyFormatSection(PreSteps(cfg)))
+
 	// Project context
 	if cfg.ProjectContext != "" {
-		b.WriteString("\n\n## Project Context\n\n")
-		b.WriteString(cfg.ProjectContext)
-		b.WriteString("\n")
+		b.WriteString("\n\n")
+		b.WriteString(ContextFragment(cfg.ProjectContext))
 	}
 
+	b.WriteString(memorySection(id, cfg.Memory))
+	b.WriteString(networkSection(cfg.Network))
+
 	return b.String()
 }
+
+// UsesBash reports whether workflows for the archetype get bash with any
+// command allowed.
+func UsesBash(archetypeID string) bool {
+	return inferCapabilities(archetypeID).bash
+}
+
+// ContextFragment renders the project context section, inline in a prompt
+// or as a shared fragment of its own.
+func ContextFragment(context string) string {
+	return "## Project Context\n\n" + context + "\n"
+}
@@ -0,0 +1,195 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single problem reported by a rule.
+type Finding struct {
+	Rule     string
+	Severity Severity
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s", f.Severity, f.Rule, f.Message)
+}
+
+type rule struct {
+	id    string
+	check func(p *data.Patterns, w *Workflow) []Finding
+}
+
+var rules = []rule{
+	{"timeout-band", checkTimeoutBand},
+	{"anti-pattern", checkAntiPatterns},
+	{"write-permissions", checkWritePermissions},
+	{"mcp-allowed", checkMCPAllowed},
+}
+
+// Lint runs every rule against a workflow.
+func Lint(p *data.Patterns, w *Workflow) []Finding {
+	var findings []Finding
+	for _, r := range rules {
+		for _, f := range r.check(p, w) {
+			f.Rule = r.id
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// HasErrors reports whether any finding is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Rules returns the IDs of the lint rules, including ImportsRule.
+func Rules() []string {
+	ids := make([]string, len(rules))
+	for i, r := range rules {
+		ids[i] = r.id
+	}
+	return append(ids, ImportsRule)
+}
+
+// SeverityOff turns a rule off in Severities.
+const SeverityOff Severity = "off"
+
+// Severities overrides the severity of rules by rule ID, e.g. to make a
+// team treat timeout-band as an error or drop anti-pattern.
+type Severities map[string]Severity
+
+// ParseSeverities validates rule IDs and severity names from a config.
+func ParseSeverities(m map[string]string) (Severities, error) {
+	s := make(Severities, len(m))
+	for id, severity := range m {
+		if !slices.Contains(Rules(), id) {
+			return nil, fmt.Errorf("unknown lint rule %q (want one of %s)", id, strings.Join(Rules(), ", "))
+		}
+		switch sev := Severity(severity); sev {
+		case SeverityError, SeverityWarning, SeverityOff:
+			s[id] = sev
+		default:
+			return nil, fmt.Errorf("lint rule %s: unknown severity %q (want error, warning or off)", id, severity)
+		}
+	}
+	return s, nil
+}
+
+// Apply re-grades findings, dropping those of rules turned off.
+func (s Severities) Apply(findings []Finding) []Finding {
+	var out []Finding
+	for _, f := range findings {
+		if sev, ok := s[f.Rule]; ok {
+			if sev == SeverityOff {
+				continue
+			}
+			f.Severity = sev
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// timeoutBandFactor is how far outside the recommended band a timeout may
+// go before it is flagged.
+const timeoutBandFactor = 2
+
+func checkTimeoutBand(p *data.Patterns, w *Workflow) []Finding {
+	timeout, ok := w.TimeoutMinutes()
+	if !ok {
+		return nil
+	}
+	triggers := w.Triggers()
+	lo, hi, ok := p.TimeoutBand(triggers)
+	if !ok {
+		return nil
+	}
+	if timeout*timeoutBandFactor >= lo && timeout <= hi*timeoutBandFactor {
+		return nil
+	}
+	band := fmt.Sprintf("%d", lo)
+	if hi != lo {
+		band = fmt.Sprintf("%d–%d", lo, hi)
+	}
+	return []Finding{{
+		Severity: SeverityWarning,
+		Message: fmt.Sprintf("timeout-minutes %d is far outside the recommended %s min for %s triggers",
+			timeout, band, strings.Join(triggers, ", ")),
+	}}
+}
+
+func checkAntiPatterns(p *data.Patterns, w *Workflow) []Finding {
+	var findings []Finding
+	for _, m := range p.MatchAntiPatterns(w.Name, w.Body) {
+		findings = append(findings, Finding{Severity: SeverityWarning, Message: m.String()})
+	}
+	return findings
+}
+
+func checkWritePermissions(_ *data.Patterns, w *Workflow) []Finding {
+	var writes []string
+	switch perms := w.Frontmatter["permissions"].(type) {
+	case string:
+		if perms == "write-all" {
+			writes = append(writes, "write-all")
+		}
+	case map[string]any:
+		for scope, level := range perms {
+			if level == "write" {
+				writes = append(writes, scope)
+			}
+		}
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+	sort.Strings(writes)
+	return []Finding{{
+		Severity: SeverityError,
+		Message: fmt.Sprintf("requests write permissions (%s) directly — use safe-outputs so writes happen outside the agent job",
+			strings.Join(writes, ", ")),
+	}}
+}
+
+// checkMCPAllowed flags MCP servers without an allowed list, which would
+// expose every tool the server offers to the agent.
+func checkMCPAllowed(_ *data.Patterns, w *Workflow) []Finding {
+	servers, _ := w.Frontmatter["mcp-servers"].(map[string]any)
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var findings []Finding
+	for _, name := range names {
+		server, _ := servers[name].(map[string]any)
+		if allowed, _ := server["allowed"].([]any); len(allowed) > 0 {
+			continue
+		}
+		findings = append(findings, Finding{
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("MCP server %q has no allowed tools — list the tools the agent may call", name),
+		})
+	}
+	return findings
+}
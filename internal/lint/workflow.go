@@ -0,0 +1,213 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Workflow is a parsed agentic workflow markdown file.
+type Workflow struct {
+	Path        string
+	Name        string
+	Frontmatter map[string]any
+	Body        string
+	// Imported is the markdown of the fragments the workflow imports, once
+	// loaded, for checks that look at the whole prompt.
+	Imported string
+}
+
+// ParseFile reads and parses the workflow at path.
+func ParseFile(path string) (*Workflow, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, string(content))
+}
+
+// Parse splits a workflow into its YAML frontmatter and markdown body. The
+// workflow name is the file name without its extension.
+func Parse(path, content string) (*Workflow, error) {
+	w := &Workflow{
+		Path: path,
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return nil, fmt.Errorf("%s: missing frontmatter", path)
+	}
+	fm, body, ok := strings.Cut(rest, "\n---\n")
+	if !ok {
+		return nil, fmt.Errorf("%s: unterminated frontmatter", path)
+	}
+	if err := yaml.Unmarshal([]byte(fm), &w.Frontmatter); err != nil {
+		return nil, fmt.Errorf("%s: parsing frontmatter: %w", path, err)
+	}
+	if w.Frontmatter == nil {
+		w.Frontmatter = map[string]any{}
+	}
+	w.Body = body
+	return w, nil
+}
+
+// Triggers returns the event names under `on:`.
+func (w *Workflow) Triggers() []string {
+	var triggers []string
+	switch on := w.Frontmatter["on"].(type) {
+	case string:
+		triggers = append(triggers, on)
+	case []any:
+		for _, t := range on {
+			if s, ok := t.(string); ok {
+				triggers = append(triggers, s)
+			}
+		}
+	case map[string]any:
+		for t := range on {
+			triggers = append(triggers, t)
+		}
+	}
+	sort.Strings(triggers)
+	return triggers
+}
+
+// Branches returns the branches an event under `on:` is limited to, or
+// nil when it runs on every branch. branches-ignore doesn't limit it.
+func (w *Workflow) Branches(event string) []string {
+	on, _ := w.Frontmatter["on"].(map[string]any)
+	cfg, _ := on[event].(map[string]any)
+	var branches []string
+	switch b := cfg["branches"].(type) {
+	case string:
+		branches = append(branches, b)
+	case []any:
+		for _, v := range b {
+			if s, ok := v.(string); ok {
+				branches = append(branches, s)
+			}
+		}
+	}
+	return branches
+}
+
+// TimeoutMinutes returns the workflow's timeout-minutes, if set.
+func (w *Workflow) TimeoutMinutes() (int, bool) {
+	t, ok := w.Frontmatter["timeout-minutes"].(int)
+	return t, ok
+}
+
+// Tools returns the names of the tools under `tools:`.
+func (w *Workflow) Tools() []string {
+	tools, _ := w.Frontmatter["tools"].(map[string]any)
+	var names []string
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bash returns the commands bash may run, and whether bash is enabled. A
+// bash entry without a list allows any command.
+func (w *Workflow) Bash() ([]string, bool) {
+	tools, _ := w.Frontmatter["tools"].(map[string]any)
+	v, ok := tools["bash"]
+	if !ok {
+		return nil, false
+	}
+	list, _ := v.([]any)
+	if len(list) == 0 {
+		return []string{"*"}, true
+	}
+	var commands []string
+	for _, c := range list {
+		if s, ok := c.(string); ok {
+			commands = append(commands, s)
+		}
+	}
+	return commands, true
+}
+
+// Toolsets returns the github tool's toolsets.
+func (w *Workflow) Toolsets() []string {
+	tools, _ := w.Frontmatter["tools"].(map[string]any)
+	github, _ := tools["github"].(map[string]any)
+	list, _ := github["toolsets"].([]any)
+	var toolsets []string
+	for _, t := range list {
+		if s, ok := t.(string); ok {
+			toolsets = append(toolsets, s)
+		}
+	}
+	return toolsets
+}
+
+// SafeOutputs returns the safe outputs the workflow declares, written
+// either as a list or as keys.
+func (w *Workflow) SafeOutputs() []string {
+	var outputs []string
+	switch so := w.Frontmatter["safe-outputs"].(type) {
+	case []any:
+		for _, o := range so {
+			if s, ok := o.(string); ok {
+				outputs = append(outputs, s)
+			}
+		}
+	case map[string]any:
+		for o := range so {
+			outputs = append(outputs, o)
+		}
+		sort.Strings(outputs)
+	}
+	return outputs
+}
+
+// Imports returns the paths under `imports:`, relative to the workflow.
+func (w *Workflow) Imports() []string {
+	list, _ := w.Frontmatter["imports"].([]any)
+	var paths []string
+	for _, p := range list {
+		if s, ok := p.(string); ok {
+			paths = append(paths, s)
+		}
+	}
+	return paths
+}
+
+// ImportsRule is the rule LoadImports reports unreadable imports under.
+const ImportsRule = "imports"
+
+// LoadImports reads the local fragments the workflow imports into
+// Imported, reporting each one that can't be read. Imports from other
+// repositories (owner/repo/path@ref) are left to gh-aw.
+func (w *Workflow) LoadImports() []Finding {
+	var b strings.Builder
+	var findings []Finding
+	for _, p := range w.Imports() {
+		if strings.Contains(p, "@") || strings.Contains(p, "://") {
+			continue
+		}
+		// A #section suffix imports part of the file.
+		file, _, _ := strings.Cut(p, "#")
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(w.Path), filepath.FromSlash(file)))
+		if err != nil {
+			findings = append(findings, Finding{
+				Rule:     ImportsRule,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("can't read import %s: %v", p, errors.Unwrap(err)),
+			})
+			continue
+		}
+		b.WriteString("\n")
+		b.Write(content)
+	}
+	w.Imported = b.String()
+	return findings
+}
@@ -0,0 +1,337 @@
+// Package policy enforces an organization's rules on generated workflows.
+// A policy is checked into the repository at .github/aw-policy.yaml and
+// declares which triggers, tools, toolsets and safe outputs workflows may
+// use, the allowed timeout range and required prompt sections. The wizard
+// disables what the policy forbids, and new and lint report violations as
+// errors naming the rule that blocked them.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+	"github.com/ashleywolf/gh-aw-create/internal/lint"
+)
+
+// File is the policy file, relative to the repository root.
+const File = ".github/aw-policy.yaml"
+
+// Policy is an organization's rules for workflows. A nil *Policy allows
+// everything.
+type Policy struct {
+	// Source is where the policy was loaded from, for messages.
+	Source string `yaml:"-"`
+
+	// Triggers are matched against event names, e.g. push or issues.
+	Triggers TriggerRule `yaml:"triggers"`
+	// Tools are matched against the keys under tools:, e.g. bash or
+	// web-fetch.
+	Tools ToolRule `yaml:"tools"`
+	// Toolsets are matched against the github tool's toolsets.
+	Toolsets    ListRule   `yaml:"toolsets"`
+	SafeOutputs ListRule   `yaml:"safe_outputs"`
+	Timeout     RangeRule  `yaml:"timeout"`
+	Prompt      PromptRule `yaml:"prompt"`
+}
+
+// ListRule allows or denies names. With Allow set only those names are
+// permitted; Deny always wins.
+type ListRule struct {
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+	// Reason explains the rule to whoever it blocks.
+	Reason string `yaml:"reason"`
+}
+
+// TriggerRule is a ListRule for events that can also restrict the
+// branches an event runs on.
+type TriggerRule struct {
+	ListRule `yaml:",inline"`
+	// Branches are matched against an event's branches filter, keyed by
+	// event name, e.g. push: {deny: [main]}. An event without a filter
+	// runs on every branch, so it breaks any branch rule for its event.
+	Branches map[string]ListRule `yaml:"branches"`
+}
+
+// ToolRule is a ListRule for tools that can also forbid unrestricted bash.
+type ToolRule struct {
+	ListRule `yaml:",inline"`
+	// RestrictBash requires bash to list the commands it may run rather
+	// than allowing any.
+	RestrictBash bool `yaml:"restrict_bash"`
+}
+
+// RangeRule bounds timeout-minutes; zero leaves a side open.
+type RangeRule struct {
+	Min    int    `yaml:"min"`
+	Max    int    `yaml:"max"`
+	Reason string `yaml:"reason"`
+}
+
+// PromptRule requires sections in the prompt body.
+type PromptRule struct {
+	// RequireSections lists headings the prompt must contain, e.g.
+	// "DO NOT".
+	RequireSections []string `yaml:"require_sections"`
+	Reason          string   `yaml:"reason"`
+}
+
+// Violation is a choice the policy forbids.
+type Violation struct {
+	// Rule is the policy rule that blocked the choice, e.g. toolsets.deny.
+	Rule    string
+	Message string
+	Reason  string
+	Source  string
+}
+
+func (v *Violation) Error() string {
+	s := fmt.Sprintf("%s (policy rule %s in %s)", v.Message, v.Rule, v.Source)
+	if v.Reason != "" {
+		s += ": " + v.Reason
+	}
+	return s
+}
+
+// Finding reports the violation as a lint error.
+func (v *Violation) Finding() lint.Finding {
+	msg := fmt.Sprintf("%s (%s)", v.Message, v.Source)
+	if v.Reason != "" {
+		msg += ": " + v.Reason
+	}
+	return lint.Finding{Rule: "policy:" + v.Rule, Severity: lint.SeverityError, Message: msg}
+}
+
+// Load reads the policy of the repository at repo. It returns nil when the
+// repository has none.
+func Load(repo string) (*Policy, error) {
+	path := filepath.Join(repo, File)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &Policy{Source: File}
+	if err := config.DecodeStrict(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Timeout.Max > 0 && p.Timeout.Min > p.Timeout.Max {
+		return nil, fmt.Errorf("%s: timeout.min %d is above timeout.max %d", path, p.Timeout.Min, p.Timeout.Max)
+	}
+	return p, nil
+}
+
+// check applies a list rule to a name, naming the rule as prefix.allow or
+// prefix.deny.
+func (p *Policy) check(r ListRule, prefix, kind, name string) error {
+	if slices.Contains(r.Deny, name) {
+		return &Violation{prefix + ".deny", fmt.Sprintf("%s %s is denied", kind, name), r.Reason, p.Source}
+	}
+	if len(r.Allow) > 0 && !slices.Contains(r.Allow, name) {
+		return &Violation{prefix + ".allow", fmt.Sprintf("%s %s is not in the allowed list", kind, name), r.Reason, p.Source}
+	}
+	return nil
+}
+
+// CheckTrigger reports whether the policy forbids an event on the given
+// branches, where nil means every branch.
+func (p *Policy) CheckTrigger(event string, branches []string) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.check(p.Triggers.ListRule, "triggers", "trigger", event); err != nil {
+		return err
+	}
+	r, ok := p.Triggers.Branches[event]
+	if !ok {
+		return nil
+	}
+	prefix := "triggers.branches." + event
+	if len(branches) == 0 {
+		switch {
+		case len(r.Deny) > 0:
+			return &Violation{prefix + ".deny", fmt.Sprintf("trigger %s runs on every branch, including %s; limit its branches", event, strings.Join(r.Deny, ", ")), r.Reason, p.Source}
+		case len(r.Allow) > 0:
+			return &Violation{prefix + ".allow", fmt.Sprintf("trigger %s runs on every branch; limit it to %s", event, strings.Join(r.Allow, ", ")), r.Reason, p.Source}
+		}
+		return nil
+	}
+	for _, b := range branches {
+		// Either side can be a pattern: the workflow's '*' covers a denied
+		// main, and a denied release/* covers the workflow's release/v1.
+		for _, d := range r.Deny {
+			if matchBranch(b, d) || matchBranch(d, b) {
+				msg := fmt.Sprintf("trigger %s on branch %s is denied", event, b)
+				if b != d {
+					msg = fmt.Sprintf("trigger %s on branch %s matches denied branch %s", event, b, d)
+				}
+				return &Violation{prefix + ".deny", msg, r.Reason, p.Source}
+			}
+		}
+		if len(r.Allow) > 0 && !slices.ContainsFunc(r.Allow, func(a string) bool { return matchBranch(a, b) }) {
+			return &Violation{prefix + ".allow", fmt.Sprintf("trigger %s on branch %s is not in the allowed list", event, b), r.Reason, p.Source}
+		}
+	}
+	return nil
+}
+
+// matchBranch reports whether a branch filter pattern matches a branch
+// name, with GitHub's globs: * stops at a slash and ** doesn't.
+func matchBranch(pattern, branch string) bool {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	ok, _ := regexp.MatchString(re.String(), branch)
+	return ok
+}
+
+// CheckTool reports whether the policy forbids a tool.
+func (p *Policy) CheckTool(name string) error {
+	if p == nil {
+		return nil
+	}
+	return p.check(p.Tools.ListRule, "tools", "tool", name)
+}
+
+// CheckBash reports whether the policy forbids bash with the given allowed
+// commands, where "*" or ":*" allows any.
+func (p *Policy) CheckBash(commands []string) error {
+	if p == nil {
+		return nil
+	}
+	if err := p.CheckTool("bash"); err != nil {
+		return err
+	}
+	if p.Tools.RestrictBash && (len(commands) == 0 || slices.Contains(commands, "*") || slices.Contains(commands, ":*")) {
+		return &Violation{"tools.restrict_bash", "unrestricted bash is not allowed; list the commands it may run", p.Tools.Reason, p.Source}
+	}
+	return nil
+}
+
+// CheckToolset reports whether the policy forbids a github toolset.
+func (p *Policy) CheckToolset(name string) error {
+	if p == nil {
+		return nil
+	}
+	return p.check(p.Toolsets, "toolsets", "toolset", name)
+}
+
+// AllowedToolsets drops the toolsets the policy forbids. The result is
+// never nil, so it can replace a workflow's inferred toolsets.
+func (p *Policy) AllowedToolsets(toolsets []string) []string {
+	allowed := []string{}
+	for _, ts := range toolsets {
+		if p.CheckToolset(ts) == nil {
+			allowed = append(allowed, ts)
+		}
+	}
+	return allowed
+}
+
+// CheckSafeOutput reports whether the policy forbids a safe output.
+func (p *Policy) CheckSafeOutput(name string) error {
+	if p == nil {
+		return nil
+	}
+	return p.check(p.SafeOutputs, "safe_outputs", "safe output", name)
+}
+
+// CheckTimeout reports whether a timeout is outside the allowed range.
+func (p *Policy) CheckTimeout(minutes int) error {
+	if p == nil {
+		return nil
+	}
+	r := p.Timeout
+	if r.Min > 0 && minutes < r.Min {
+		return &Violation{"timeout.min", fmt.Sprintf("timeout %d min is below the minimum of %d", minutes, r.Min), r.Reason, p.Source}
+	}
+	if r.Max > 0 && minutes > r.Max {
+		return &Violation{"timeout.max", fmt.Sprintf("timeout %d min is above the maximum of %d", minutes, r.Max), r.Reason, p.Source}
+	}
+	return nil
+}
+
+// CheckPrompt reports the required sections missing from a prompt body.
+func (p *Policy) CheckPrompt(body string) []error {
+	if p == nil {
+		return nil
+	}
+	var errs []error
+	for _, section := range p.Prompt.RequireSections {
+		heading := regexp.MustCompile(`(?mi)^#{1,6}\s+` + regexp.QuoteMeta(section) + `\s*$`)
+		if !heading.MatchString(body) {
+			errs = append(errs, &Violation{"prompt.require_sections", fmt.Sprintf("the prompt has no %q section", section), p.Prompt.Reason, p.Source})
+		}
+	}
+	return errs
+}
+
+// Check reports every violation in a workflow.
+func (p *Policy) Check(w *lint.Workflow) []*Violation {
+	if p == nil {
+		return nil
+	}
+	var errs []error
+	for _, t := range w.Triggers() {
+		errs = append(errs, p.CheckTrigger(t, w.Branches(t)))
+	}
+	for _, t := range w.Tools() {
+		if t != "bash" {
+			errs = append(errs, p.CheckTool(t))
+		}
+	}
+	if commands, ok := w.Bash(); ok {
+		errs = append(errs, p.CheckBash(commands))
+	}
+	for _, ts := range w.Toolsets() {
+		errs = append(errs, p.CheckToolset(ts))
+	}
+	for _, o := range w.SafeOutputs() {
+		errs = append(errs, p.CheckSafeOutput(o))
+	}
+	if t, ok := w.TimeoutMinutes(); ok {
+		errs = append(errs, p.CheckTimeout(t))
+	}
+	// A required section may come from an imported fragment.
+	errs = append(errs, p.CheckPrompt(w.Body+w.Imported)...)
+
+	var violations []*Violation
+	for _, err := range errs {
+		var v *Violation
+		if errors.As(err, &v) {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+// Findings reports violations as lint errors.
+func Findings(violations []*Violation) []lint.Finding {
+	var findings []lint.Finding
+	for _, v := range violations {
+		findings = append(findings, v.Finding())
+	}
+	return findings
+}
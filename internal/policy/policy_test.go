@@ -0,0 +1,257 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ashleywolf/gh-aw-create/internal/lint"
+)
+
+// rules returns the rule of each violation.
+func rules(violations []*Violation) []string {
+	var names []string
+	for _, v := range violations {
+		names = append(names, v.Rule)
+	}
+	return names
+}
+
+// ruleOf returns the rule of a check's error, or "" for none.
+func ruleOf(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.(*Violation).Rule
+}
+
+func TestCheckTrigger(t *testing.T) {
+	p := &Policy{Source: File, Triggers: TriggerRule{
+		ListRule: ListRule{Allow: []string{"push", "issues", "schedule"}, Deny: []string{"issues"}},
+		Branches: map[string]ListRule{"push": {Deny: []string{"main", "release/*"}}},
+	}}
+	tests := []struct {
+		name     string
+		event    string
+		branches []string
+		want     string
+	}{
+		{"allowed", "schedule", nil, ""},
+		{"deny wins over allow", "issues", nil, "triggers.deny"},
+		{"not in allow", "pull_request", nil, "triggers.allow"},
+		{"allowed branch", "push", []string{"dev"}, ""},
+		{"denied branch", "push", []string{"dev", "main"}, "triggers.branches.push.deny"},
+		{"every branch", "push", nil, "triggers.branches.push.deny"},
+		{"star covers main", "push", []string{"*"}, "triggers.branches.push.deny"},
+		{"denied pattern", "push", []string{"release/*"}, "triggers.branches.push.deny"},
+		{"branch under denied pattern", "push", []string{"release/v1"}, "triggers.branches.push.deny"},
+		{"denied pattern stops at slash", "push", []string{"release/v1/hotfix"}, ""},
+		{"pattern under denied pattern", "push", []string{"release/v*"}, "triggers.branches.push.deny"},
+		{"star stops at slash", "push", []string{"feature-*"}, ""},
+		{"double star crosses slashes", "push", []string{"**"}, "triggers.branches.push.deny"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleOf(p.CheckTrigger(tt.event, tt.branches)); got != tt.want {
+				t.Errorf("CheckTrigger(%q, %q) = %q, want %q", tt.event, tt.branches, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTriggerBranchAllow(t *testing.T) {
+	p := &Policy{Source: File, Triggers: TriggerRule{
+		Branches: map[string]ListRule{"push": {Allow: []string{"dev", "release/**"}}},
+	}}
+	tests := []struct {
+		branches []string
+		want     string
+	}{
+		{[]string{"dev"}, ""},
+		{[]string{"dev", "main"}, "triggers.branches.push.allow"},
+		{nil, "triggers.branches.push.allow"},
+		{[]string{"release/v1"}, ""},
+		{[]string{"release/v1/hotfix"}, ""},
+		{[]string{"release/*"}, ""},
+		{[]string{"releases"}, "triggers.branches.push.allow"},
+		{[]string{"*"}, "triggers.branches.push.allow"},
+	}
+	for _, tt := range tests {
+		if got := ruleOf(p.CheckTrigger("push", tt.branches)); got != tt.want {
+			t.Errorf("CheckTrigger(push, %q) = %q, want %q", tt.branches, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBash(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *Policy
+		commands []string
+		want     string
+	}{
+		{"no policy", nil, []string{"*"}, ""},
+		{"unrestricted allowed", &Policy{}, []string{"*"}, ""},
+		{"restricted star", &Policy{Tools: ToolRule{RestrictBash: true}}, []string{"*"}, "tools.restrict_bash"},
+		{"restricted colon star", &Policy{Tools: ToolRule{RestrictBash: true}}, []string{"ls", ":*"}, "tools.restrict_bash"},
+		{"restricted empty", &Policy{Tools: ToolRule{RestrictBash: true}}, nil, "tools.restrict_bash"},
+		{"restricted list", &Policy{Tools: ToolRule{RestrictBash: true}}, []string{"ls", "cat"}, ""},
+		{"bash denied", &Policy{Tools: ToolRule{ListRule: ListRule{Deny: []string{"bash"}}}}, []string{"ls"}, "tools.deny"},
+		{"bash not allowed", &Policy{Tools: ToolRule{ListRule: ListRule{Allow: []string{"edit"}}}}, []string{"ls"}, "tools.allow"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleOf(tt.policy.CheckBash(tt.commands)); got != tt.want {
+				t.Errorf("CheckBash(%q) = %q, want %q", tt.commands, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	p := &Policy{Timeout: RangeRule{Min: 5, Max: 30}}
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{4, "timeout.min"},
+		{5, ""},
+		{30, ""},
+		{31, "timeout.max"},
+	}
+	for _, tt := range tests {
+		if got := ruleOf(p.CheckTimeout(tt.minutes)); got != tt.want {
+			t.Errorf("CheckTimeout(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+	open := &Policy{Timeout: RangeRule{Max: 10}}
+	if err := open.CheckTimeout(1); err != nil {
+		t.Errorf("CheckTimeout(1) with no minimum = %v, want nil", err)
+	}
+}
+
+func TestCheckPrompt(t *testing.T) {
+	p := &Policy{Prompt: PromptRule{RequireSections: []string{"DO NOT", "Steps"}}}
+	tests := []struct {
+		name    string
+		body    string
+		missing int
+	}{
+		{"both", "# Task\n\n## Steps\n1. a\n\n## DO NOT\n- b\n", 0},
+		{"case and level", "### do not\n#### steps  \n", 0},
+		{"one missing", "## Steps\n", 1},
+		{"mentioned, not a heading", "Please DO NOT do this.\nSteps: none\n", 2},
+		{"heading with more text", "## DO NOT EVER\n## Steps\n", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := len(p.CheckPrompt(tt.body)); got != tt.missing {
+				t.Errorf("CheckPrompt missing %d sections, want %d", got, tt.missing)
+			}
+		})
+	}
+}
+
+const workflow = `---
+description: "Test"
+imports:
+  - shared/do-not.md
+on:
+  push:
+    branches: [main]
+  issues:
+tools:
+  bash: [":*"]
+  github:
+    toolsets: [repos, code_security]
+safe-outputs:
+  add-comment:
+  create-pull-request:
+timeout-minutes: 45
+---
+
+# Test
+`
+
+func TestCheck(t *testing.T) {
+	p := &Policy{
+		Source: File,
+		Triggers: TriggerRule{
+			Branches: map[string]ListRule{"push": {Deny: []string{"main"}}},
+		},
+		Tools:       ToolRule{RestrictBash: true},
+		Toolsets:    ListRule{Deny: []string{"code_security"}},
+		SafeOutputs: ListRule{Allow: []string{"add-comment"}},
+		Timeout:     RangeRule{Max: 30},
+		Prompt:      PromptRule{RequireSections: []string{"DO NOT"}},
+	}
+	w, err := lint.Parse("wf/test.md", workflow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"triggers.branches.push.deny", "tools.restrict_bash", "toolsets.deny",
+		"safe_outputs.allow", "timeout.max", "prompt.require_sections"}
+	if got := rules(p.Check(w)); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Check = %q, want %q", got, want)
+	}
+
+	// A required section can come from an imported fragment.
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "shared"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shared", "do-not.md"), []byte("## DO NOT\n\n- Push\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w.Path = filepath.Join(dir, "test.md")
+	if findings := w.LoadImports(); len(findings) > 0 {
+		t.Fatal(findings)
+	}
+	for _, rule := range rules(p.Check(w)) {
+		if rule == "prompt.require_sections" {
+			t.Errorf("Check reports the missing section the import provides")
+		}
+	}
+
+	var none *Policy
+	if got := none.Check(w); got != nil {
+		t.Errorf("nil policy Check = %v, want nil", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if p, err := Load(dir); p != nil || err != nil {
+		t.Fatalf("Load without a file = %v, %v, want nil, nil", p, err)
+	}
+
+	write := func(content string) {
+		t.Helper()
+		path := filepath.Join(dir, File)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("triggers:\n  branches:\n    push:\n      deny: [main]\n")
+	p, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Triggers.Branches["push"].Deny; len(got) != 1 || got[0] != "main" {
+		t.Errorf("branches.push.deny = %q, want [main]", got)
+	}
+
+	write("triggers:\n  dney: [push]\n")
+	if _, err := Load(dir); err == nil {
+		t.Error("Load accepted a misspelt rule")
+	}
+	write("timeout:\n  min: 30\n  max: 10\n")
+	if _, err := Load(dir); err == nil {
+		t.Error("Load accepted min above max")
+	}
+}
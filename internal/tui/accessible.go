@@ -0,0 +1,686 @@
+package tui
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/fragment"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// Accessible mode asks the wizard's questions as plain line prompts with
+// numbered choices, for screen readers and dumb terminals. It fills in the
+// same Model the TUI does and generates from its config, so the written
+// workflow is identical for the same answers.
+
+// errInputEnded is returned when input runs out mid-wizard.
+var errInputEnded = errors.New("input ended before the wizard finished")
+
+// prompter reads answers line by line.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+func (p prompter) say(format string, args ...any) {
+	fmt.Fprintf(p.out, format+"\n", args...)
+}
+
+// ask prints a question and returns the trimmed answer, or def when the
+// answer is empty.
+func (p prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		fmt.Fprintln(p.out)
+		return "", errInputEnded
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	return def, nil
+}
+
+// askValid asks until check accepts the answer.
+func (p prompter) askValid(question, def string, check func(string) error) (string, error) {
+	for {
+		answer, err := p.ask(question, def)
+		if err != nil {
+			return "", err
+		}
+		if err := check(answer); err != nil {
+			p.say("  %s", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// choose lists numbered options and returns the index picked; def is the
+// index chosen by an empty answer.
+func (p prompter) choose(question string, options []string, def int) (int, error) {
+	p.say("%s", question)
+	for i, o := range options {
+		p.say("  %d. %s", i+1, o)
+	}
+	var picked int
+	_, err := p.askValid(fmt.Sprintf("Choose 1-%d", len(options)), strconv.Itoa(def+1), func(s string) error {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > len(options) {
+			return fmt.Errorf("enter a number from 1 to %d", len(options))
+		}
+		picked = n - 1
+		return nil
+	})
+	return picked, err
+}
+
+// chooseMany lists numbered options with the current selection marked and
+// returns the new selection. An empty answer keeps the selection and
+// "none" clears it.
+func (p prompter) chooseMany(question string, options []string, selected []bool) ([]bool, error) {
+	p.say("%s", question)
+	var current []string
+	for i, o := range options {
+		mark := "  "
+		if selected[i] {
+			mark = "* "
+			current = append(current, strconv.Itoa(i+1))
+		}
+		p.say("  %s%d. %s", mark, i+1, o)
+	}
+	def := strings.Join(current, " ")
+	if def == "" {
+		def = "none"
+	}
+	next := make([]bool, len(options))
+	_, err := p.askValid("Numbers separated by spaces, or none", def, func(s string) error {
+		clear(next)
+		if s == "none" {
+			return nil
+		}
+		for _, f := range strings.Fields(strings.ReplaceAll(s, ",", " ")) {
+			n, err := strconv.Atoi(f)
+			if err != nil || n < 1 || n > len(options) {
+				return fmt.Errorf("%q isn't a number from 1 to %d", f, len(options))
+			}
+			next[n-1] = true
+		}
+		return nil
+	})
+	return next, err
+}
+
+func (p prompter) confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	var yes bool
+	_, err := p.askValid(question+" ("+hint+")", "", func(s string) error {
+		switch strings.ToLower(s) {
+		case "":
+			yes = def
+		case "y", "yes":
+			yes = true
+		case "n", "no":
+			yes = false
+		default:
+			return fmt.Errorf("answer y or n")
+		}
+		return nil
+	})
+	return yes, err
+}
+
+// RunAccessible runs the wizard as line prompts on in and out, writing the
+// workflow when the user confirms.
+func RunAccessible(p *data.Patterns, opts Options, in io.Reader, out io.Writer) error {
+	m := NewModel(p, opts)
+	a := prompter{in: bufio.NewReader(in), out: out}
+	a.say("Create a GitHub Agentic Workflow. Press enter to accept the default in brackets.")
+
+	steps := []func(*Model, prompter) error{
+		(*Model).askArchetype,
+		(*Model).askTriggers,
+		(*Model).askContext,
+		(*Model).askTools,
+		(*Model).askPreSteps,
+		(*Model).askEngine,
+		(*Model).askNetwork,
+	}
+	for i, ask := range steps {
+		m.step = step(i)
+		a.say("\nStep %d of %d: %s", i+1, len(stepLabels), stepLabels[i])
+		if err := ask(&m, a); err != nil {
+			return err
+		}
+	}
+	m.step = stepPreview
+	a.say("\nStep %d of %d: %s", len(stepLabels), len(stepLabels), stepLabels[stepPreview])
+	return m.askWrite(a)
+}
+
+// sayTips prints the archetype's tips for a step.
+func (m Model) sayTips(a prompter, step data.TipStep) {
+	for _, r := range m.activeTips(step) {
+		a.say("Tip: %s", r.Tip)
+	}
+}
+
+func (m *Model) askArchetype(a prompter) error {
+	var options []string
+	for _, arch := range m.patterns.Archetypes {
+		o := fmt.Sprintf("%s — %s (%.0f%% success)", arch.Label, arch.Description, arch.SuccessRate*100)
+		if err := m.archetypeBlocked(arch); err != nil {
+			o += " (blocked: " + err.Error() + ")"
+		}
+		options = append(options, o)
+	}
+	for {
+		i, err := a.choose("What type of workflow?", options, 0)
+		if err != nil {
+			return err
+		}
+		if err := m.archetypeBlocked(m.patterns.Archetypes[i]); err != nil {
+			a.say("  Blocked: %s", err)
+			continue
+		}
+		m.archCursor = i
+		m.resetForArchetype()
+		return nil
+	}
+}
+
+func (m *Model) askTriggers(a prompter) error {
+	m.sayTips(a, data.TipTriggers)
+	var options []string
+	var selected []bool
+	for _, t := range m.patterns.Triggers {
+		o := t.ID + " — " + t.Description
+		if t.Risk != "" && t.Risk != data.RiskLow {
+			o += " (" + t.Risk + " risk)"
+		}
+		if err := m.triggerBlocked(t); err != nil {
+			o += " (blocked: " + err.Error() + ")"
+		}
+		options = append(options, o)
+		selected = append(selected, m.triggerSelected[t.ID])
+	}
+	for {
+		next, err := a.chooseMany("Triggers (recommended ones are marked *):", options, selected)
+		if err != nil {
+			return err
+		}
+		var blocked error
+		m.triggerSelected = make(map[string]bool)
+		for i, t := range m.patterns.Triggers {
+			if next[i] {
+				m.triggerSelected[t.ID] = true
+				blocked = cmp.Or(blocked, m.triggerBlocked(t))
+			}
+		}
+		switch {
+		case blocked != nil:
+			a.say("  Blocked: %s", blocked)
+		case len(m.selectedTriggers()) == 0:
+			a.say("  Select at least one trigger")
+		default:
+			return nil
+		}
+	}
+}
+
+func (m *Model) askContext(a prompter) error {
+	m.sayTips(a, data.TipContext)
+	name, err := a.askValid("Workflow name", m.defaultWorkflowName(), func(s string) error {
+		m.nameInput.SetValue(s)
+		_, err := m.workflowName()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if name == m.defaultWorkflowName() {
+		m.nameInput.SetValue("")
+	}
+	for _, match := range m.patterns.MatchAntiPatterns(name, "") {
+		a.say("  Warning: %s", match)
+	}
+
+	question := "Project context (optional)"
+	if m.contextInput.Value() != "" {
+		question = "Project context (- for none)"
+	}
+	context, err := a.ask(question, m.contextInput.Value())
+	if err != nil {
+		return err
+	}
+	if context == "-" {
+		context = ""
+	}
+	m.contextInput.SetValue(context)
+
+	defaults := m.resolvedTimeout()
+	timeout, err := a.askValid(fmt.Sprintf("Timeout in minutes (%s)", defaults.Source), strconv.Itoa(defaults.Minutes), func(s string) error {
+		m.timeoutInput.SetValue(s)
+		if _, err := m.timeoutOverride(); err != nil {
+			return err
+		}
+		return m.timeoutBlocked()
+	})
+	if err != nil {
+		return err
+	}
+	if timeout == strconv.Itoa(defaults.Minutes) {
+		m.timeoutInput.SetValue("")
+	}
+
+	if err := m.askMemory(a); err != nil {
+		return err
+	}
+	return m.askImports(a)
+}
+
+func (m *Model) askMemory(a prompter) error {
+	kinds := []string{
+		"Off",
+		"Cache — kept in the Actions cache, entries expire",
+		"Repo branch — committed to a memory/ branch, kept indefinitely",
+	}
+	i, err := a.choose("Remember across runs?", kinds, slices.Index(generator.MemoryKinds, m.memoryKind))
+	if err != nil {
+		return err
+	}
+	m.memoryKind = generator.MemoryKinds[i]
+	if m.memoryKind == generator.MemoryNone {
+		return nil
+	}
+	if m.memoryKind == generator.MemoryCache {
+		if _, err := a.askValid("Retention in days (empty for the default of 7)", "", func(s string) error {
+			m.retentionInput.SetValue(s)
+			_, err := m.memoryRetention()
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	_, err = a.askValid("Memory key (empty for the default)", "", func(s string) error {
+		m.memoryKeyInput.SetValue(s)
+		_, err := m.memoryConfig()
+		return err
+	})
+	return err
+}
+
+// askImports offers the repository's shared fragments, and saving the
+// project context as one.
+func (m *Model) askImports(a prompter) error {
+	if len(m.fragments) > 0 {
+		var options []string
+		var selected []bool
+		for _, f := range m.fragments {
+			options = append(options, f.Title+" ("+f.Path+")")
+			selected = append(selected, slices.Contains(m.imports, f.Path))
+		}
+		chosen, err := a.chooseMany("Import shared fragments?", options, selected)
+		if err != nil {
+			return err
+		}
+		m.imports = nil
+		for i, on := range chosen {
+			if on {
+				m.imports = append(m.imports, m.fragments[i].Path)
+			}
+		}
+	}
+	if strings.TrimSpace(m.contextInput.Value()) == "" {
+		m.shareContext = false
+		return nil
+	}
+	share, err := a.confirm("Save the project context as "+fragment.ContextFile+" and import it?", m.shareContext)
+	m.shareContext = share
+	return err
+}
+
+func (m *Model) askTools(a prompter) error {
+	current := generator.Toolsets(m.workflowConfig())
+	var options []string
+	var selected []bool
+	for _, ts := range generator.GitHubToolsets {
+		o := ts
+		if err := m.toolsetBlocked(ts); err != nil {
+			o += " (blocked: " + err.Error() + ")"
+		}
+		options = append(options, o)
+		selected = append(selected, slices.Contains(current, ts))
+	}
+	var next []bool
+	for {
+		var err error
+		next, err = a.chooseMany("GitHub toolsets (inferred ones are marked *):", options, selected)
+		if err != nil {
+			return err
+		}
+		var blocked error
+		for i, ts := range generator.GitHubToolsets {
+			if next[i] {
+				blocked = cmp.Or(blocked, m.toolsetBlocked(ts))
+			}
+		}
+		if blocked == nil {
+			break
+		}
+		a.say("  Blocked: %s", blocked)
+	}
+	// Only record a choice when it differs, so the archetype's inferred
+	// toolsets keep applying otherwise.
+	if !slices.Equal(next, selected) {
+		m.toolsets = []string{}
+		for i, ts := range generator.GitHubToolsets {
+			if next[i] {
+				m.toolsets = append(m.toolsets, ts)
+			}
+		}
+	}
+
+	if err := m.askPermissions(a); err != nil {
+		return err
+	}
+
+	if choices := m.mcpCatalogChoices(); len(choices) > 0 {
+		options = nil
+		for _, s := range choices {
+			options = append(options, s.Name+" — "+s.Description)
+		}
+		picked, err := a.chooseMany("MCP servers to add:", options, make([]bool, len(options)))
+		if err != nil {
+			return err
+		}
+		for i, s := range choices {
+			if picked[i] {
+				m.mcpServers = append(m.mcpServers, s)
+			}
+		}
+	}
+	for {
+		add, err := a.confirm("Add a custom MCP server?", false)
+		if err != nil || !add {
+			return err
+		}
+		if err := m.askMCPServer(a); err != nil {
+			return err
+		}
+	}
+}
+
+// askPermissions shows the permissions derived from the tools and takes
+// overrides as scope=level pairs.
+func (m *Model) askPermissions(a prompter) error {
+	levels := []string{generator.PermissionNone, generator.PermissionRead, generator.PermissionWrite}
+	a.say("Permissions:")
+	perms := m.permissions()
+	for _, scope := range generator.PermissionScopes {
+		a.say("  %s: %s", scope, cmp.Or(perms[scope], generator.PermissionNone))
+	}
+	overrides := map[string]string{}
+	_, err := a.askValid("Changes as scope=level (none, read or write), separated by spaces (optional)", "", func(s string) error {
+		clear(overrides)
+		for _, f := range strings.Fields(strings.ReplaceAll(s, ",", " ")) {
+			scope, level, _ := strings.Cut(f, "=")
+			if !slices.Contains(generator.PermissionScopes, scope) {
+				return fmt.Errorf("unknown scope %q (want one of %s)", scope, strings.Join(generator.PermissionScopes, ", "))
+			}
+			if !slices.Contains(levels, level) {
+				return fmt.Errorf("%s: unknown level %q (want none, read or write)", scope, level)
+			}
+			overrides[scope] = level
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for scope, level := range overrides {
+		if level != cmp.Or(perms[scope], generator.PermissionNone) {
+			m.permOverrides[scope] = level
+		}
+	}
+	var writes []string
+	for _, scope := range generator.PermissionScopes {
+		if m.permissions()[scope] == generator.PermissionWrite {
+			writes = append(writes, scope)
+		}
+	}
+	if len(writes) > 0 {
+		a.say("  Warning: write access to %s — prefer safe-outputs, which apply writes in a separate job after the agent finishes", strings.Join(writes, ", "))
+	}
+	return nil
+}
+
+// askMCPServer asks for a custom server's settings, as the TUI's form
+// does, until they are valid.
+func (m *Model) askMCPServer(a prompter) error {
+	m.mcpEditing = -1
+	m.mcpDescription = ""
+	for i := range m.mcpInputs {
+		m.mcpInputs[i].SetValue("")
+	}
+	// After a mistake the earlier answers are the defaults.
+	for {
+		for i, q := range [mcpFieldCount]string{
+			"Name",
+			"Command and arguments, quoting any with spaces, or an http(s) URL",
+			"Env as KEY=value, comma-separated (optional)",
+			"Allowed tool names, comma-separated",
+		} {
+			v, err := a.ask(q, m.mcpInputs[i].Value())
+			if err != nil {
+				return err
+			}
+			m.mcpInputs[i].SetValue(v)
+		}
+		s, err := m.mcpFormServer()
+		if err == nil {
+			m.mcpServers = append(m.mcpServers, s)
+			return nil
+		}
+		a.say("  %s", err)
+	}
+}
+
+func (m *Model) askPreSteps(a prompter) error {
+	m.sayTips(a, data.TipPreSteps)
+	current := m.preStepIDs()
+	var options []string
+	var selected []bool
+	for _, r := range data.PreStepRecipes {
+		options = append(options, fmt.Sprintf("%s — %s, writes %s", r.Label, r.Description, r.Output))
+		selected = append(selected, slices.Contains(current, r.ID))
+	}
+	next, err := a.chooseMany("Data to fetch before the agent starts (defaults are marked *):", options, selected)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(next, selected) {
+		m.preSteps = []string{}
+		for i, r := range data.PreStepRecipes {
+			if next[i] {
+				m.preSteps = append(m.preSteps, r.ID)
+			}
+		}
+	}
+
+	for _, id := range m.preStepIDs() {
+		r, _ := data.FindPreStepRecipe(id)
+		for _, param := range m.recipeParams(r) {
+			_, err := a.askValid(fmt.Sprintf("%s: %s", r.Label, param.Label), param.Default, func(s string) error {
+				m.setPreStepParam(r, param, s)
+				return m.preStepErr()
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// setPreStepParam records a parameter value as the TUI does, leaving it
+// unset when it matches the recipe's default.
+func (m *Model) setPreStepParam(r data.PreStepRecipe, p data.PreStepParam, value string) {
+	values := make(map[string]string)
+	maps.Copy(values, m.preStepParams[r.ID])
+	delete(values, p.Key)
+	if value != p.Default {
+		values[p.Key] = value
+	}
+	params := make(map[string]map[string]string)
+	maps.Copy(params, m.preStepParams)
+	params[r.ID] = values
+	if len(values) == 0 {
+		delete(params, r.ID)
+	}
+	if len(params) == 0 {
+		params = nil
+	}
+	m.preStepParams = params
+}
+
+func (m *Model) askEngine(a prompter) error {
+	var options []string
+	for _, e := range data.Engines {
+		o := e.Label + " — " + e.Description
+		if e.Secret != "" {
+			o += ", needs " + e.Secret
+		}
+		options = append(options, o)
+	}
+	i, err := a.choose("AI engine:", options, m.engineCursor)
+	if err != nil {
+		return err
+	}
+	m.engineCursor = i
+	for _, f := range m.engineFields() {
+		_, err := a.askValid(f.label+" (optional)", f.input.Value(), func(s string) error {
+			f.input.SetValue(s)
+			return m.engineErr()
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Model) askNetwork(a prompter) error {
+	m.sayTips(a, data.TipNetwork)
+	var options []string
+	var selected []bool
+	for _, p := range data.NetworkPresets {
+		o := p.Label + " — " + strings.Join(p.Domains, ", ")
+		if slices.Contains(m.detectedNetwork, p.ID) {
+			o += " (detected)"
+		}
+		options = append(options, o)
+		selected = append(selected, slices.Contains(m.networkEcosystems, p.ID))
+	}
+	next, err := a.chooseMany("Network access for the agent:", options, selected)
+	if err != nil {
+		return err
+	}
+	m.networkEcosystems = nil
+	for i, p := range data.NetworkPresets {
+		if next[i] {
+			m.networkEcosystems = append(m.networkEcosystems, p.ID)
+		}
+	}
+
+	if m.networkDeny, err = a.confirm("Default-deny, dropping gh-aw's default infrastructure allowlist?", false); err != nil {
+		return err
+	}
+	_, err = a.askValid("Custom domains, separated by spaces (optional)", "", func(s string) error {
+		var domains []string
+		for _, d := range strings.Fields(strings.ReplaceAll(s, ",", " ")) {
+			d = strings.ToLower(d)
+			if err := data.ValidateDomain(d); err != nil {
+				return fmt.Errorf("%s: %w", d, err)
+			}
+			if !slices.Contains(domains, d) {
+				domains = append(domains, d)
+			}
+		}
+		m.networkDomains = domains
+		return nil
+	})
+	return err
+}
+
+// askWrite shows the workflow and its lint findings, then writes it once
+// confirmed.
+func (m *Model) askWrite(a prompter) error {
+	m.generateWorkflow()
+	for _, c := range m.changesFromDefaults() {
+		a.say("Changed from defaults: %s", c)
+	}
+	m.sayTips(a, data.TipPreview)
+	a.say("--- %s ---", m.outputPath())
+	a.say("%s", strings.TrimRight(m.generated, "\n"))
+	a.say("--- end of %s ---", m.outputPath())
+	for _, f := range m.importedFragments() {
+		a.say("--- imported from %s ---", f.Path)
+		a.say("%s", strings.TrimRight(f.Content, "\n"))
+		a.say("--- end of %s ---", f.Path)
+	}
+	for _, v := range m.violations {
+		a.say("%s", v.Finding())
+	}
+	for _, f := range m.findings {
+		a.say("%s", f)
+	}
+	if len(m.violations) > 0 {
+		return fmt.Errorf("not written: the workflow violates %s", m.policy.Source)
+	}
+
+	write, err := a.confirm("Write "+m.outputPath()+"?", len(m.findings) == 0)
+	if err != nil {
+		return err
+	}
+	if !write {
+		a.say("Not written.")
+		return nil
+	}
+	m.writePath = m.outputPath()
+	if err := m.writeContextFragment(); err != nil {
+		return err
+	}
+	if m.sharingContext() {
+		a.say("Written to %s", fragment.ContextFile)
+	}
+	if err := writeWorkflowFile(m.writePath, m.generated); err != nil {
+		return err
+	}
+	m.written = true
+	a.say("Written to %s", m.writePath)
+	a.say("\nNext steps:")
+	for _, s := range m.nextSteps() {
+		if s.cmd != "" {
+			a.say("  %s. %s: %s", s.num, s.desc, s.cmd)
+		} else {
+			a.say("  %s. %s", s.num, s.desc)
+		}
+	}
+	return nil
+}
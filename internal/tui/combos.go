@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+// maxComboSuggestions caps how many trigger changes are suggested at once.
+const maxComboSuggestions = 2
+
+// renderComboFeedback shows the recorded success rate and risk for the
+// selected trigger combination, plus changes that would improve it.
+func (m Model) renderComboFeedback() string {
+	triggers := data.TriggerEvents(m.selectedTriggers())
+	if len(triggers) == 0 {
+		return "  " + m.styles.Warning.Render(m.styles.WarnIcon+" Select at least one trigger") + "\n"
+	}
+
+	var b strings.Builder
+	match, ok := m.patterns.MatchCombo(triggers)
+	switch {
+	case !ok:
+		b.WriteString("  " + m.styles.ItemDescInline.Render("No research data for this combination") + "\n")
+	default:
+		label := "Combination"
+		if !match.Exact {
+			label = "Nearest known"
+		}
+		b.WriteString(fmt.Sprintf("  %s %s  %s %s\n",
+			m.styles.DetailLabel.Render(label),
+			match.Combo,
+			m.styles.successStyle(match.SuccessRate).Render(fmt.Sprintf("%.0f%%", match.SuccessRate*100)),
+			m.styles.ItemDescInline.Render(fmt.Sprintf("success · %d workflows · %s risk", match.Count, match.Risk))))
+	}
+
+	suggestions := m.patterns.SuggestCombos(triggers)
+	if len(suggestions) > maxComboSuggestions {
+		suggestions = suggestions[:maxComboSuggestions]
+	}
+	for _, s := range suggestions {
+		verb := "removing"
+		if s.Add {
+			verb = "adding"
+		}
+		b.WriteString("  " + m.styles.Tip.Render(fmt.Sprintf("%s %s %s raises expected success to %.0f%%", m.styles.UpArrow, verb, s.Trigger, s.SuccessRate*100)) + "\n")
+	}
+	return b.String()
+}
+
+// toggleTrigger flips a trigger, deselecting other catalog entries that
+// render under the same event since only one can be emitted.
+func (m *Model) toggleTrigger(t data.TriggerSpec) {
+	if m.triggerSelected[t.ID] {
+		delete(m.triggerSelected, t.ID)
+		return
+	}
+	if m.triggerBlocked(t) != nil {
+		return
+	}
+	for _, other := range m.patterns.Triggers {
+		if other.ID != t.ID && other.EventName() == t.EventName() {
+			delete(m.triggerSelected, other.ID)
+		}
+	}
+	m.triggerSelected[t.ID] = true
+}
+
+// renderTriggerWarnings surfaces the research findings behind risky
+// triggers in the current selection.
+func (m Model) renderTriggerWarnings() string {
+	var b strings.Builder
+	for _, t := range m.selectedTriggers() {
+		if t.Finding == "" {
+			continue
+		}
+		finding, ok := m.patterns.ResearchFindings[t.Finding]
+		if !ok {
+			continue
+		}
+		b.WriteString(m.renderWarning(m.styles.Warning, fmt.Sprintf("%s: %s", t.ID, finding)))
+	}
+	return b.String()
+}
+
+func (s Styles) riskStyle(risk string) lipgloss.Style {
+	switch risk {
+	case data.RiskHigh:
+		return s.Error
+	case data.RiskMedium:
+		return s.Warning
+	default:
+		return s.Checked
+	}
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+// RenderArchetypeDetails renders the research data for an archetype: success
+// stats, recommended configuration, top repos, tips and anti-patterns.
+func (s Styles) RenderArchetypeDetails(a data.Archetype) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("%s %s\n", s.ArchetypeIcon(a.ID), s.Title.UnsetMarginBottom().Render(a.Label)))
+	b.WriteString(s.Subtitle.Render(a.Description))
+	b.WriteString("\n")
+
+	row := func(label, value string) {
+		b.WriteString(s.DetailLabel.Render(label))
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	row("Success rate", fmt.Sprintf("%s %s",
+		s.successStyle(a.SuccessRate).Render(fmt.Sprintf("%.0f%%", a.SuccessRate*100)),
+		s.ItemDescInline.Render(fmt.Sprintf("%s  %d workflows", s.successBar(a.SuccessRate, 10), a.Count))))
+	if len(a.SizeRangeBytes) == 2 {
+		row("Prompt size", fmt.Sprintf("%s–%s KB", formatKB(a.SizeRangeBytes[0]), formatKB(a.SizeRangeBytes[1])))
+	}
+	if a.PromptStyle != "" {
+		row("Prompt style", a.PromptStyle)
+	}
+	if a.TimeoutMinutes > 0 {
+		row("Timeout", fmt.Sprintf("%d min", a.TimeoutMinutes))
+	}
+
+	var triggers []string
+	for _, t := range a.RecommendedTriggers {
+		triggers = append(triggers, t.Type)
+	}
+	row("Triggers", s.joinOrNone(triggers))
+	row("Safe outputs", s.joinOrNone(a.RecommendedSafeOutputs))
+	if len(a.RecommendedTools) > 0 {
+		row("Tools", strings.Join(a.RecommendedTools, ", "))
+	}
+
+	if len(a.TopRepos) > 0 {
+		b.WriteString("\n")
+		b.WriteString(s.DetailHeading.Render("Top repos"))
+		b.WriteString("\n")
+		for _, r := range a.TopRepos {
+			b.WriteString(fmt.Sprintf("  %s %s\n", r.Repo, s.ItemDescInline.Render(s.StarIcon+" "+formatStars(r.Stars))))
+		}
+	}
+
+	if len(a.Tips) > 0 {
+		b.WriteString("\n")
+		b.WriteString(s.DetailHeading.Render("Tips"))
+		b.WriteString("\n")
+		for _, t := range a.Tips {
+			b.WriteString(fmt.Sprintf("  %s %s\n", s.Bullet, t))
+		}
+	}
+
+	if len(a.AntiPatterns) > 0 {
+		b.WriteString("\n")
+		b.WriteString(s.DetailHeading.Render("Avoid copying"))
+		b.WriteString("\n")
+		b.WriteString("  " + s.ItemDescInline.Render(strings.Join(a.AntiPatterns, ", ")))
+		b.WriteString("\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (s Styles) successStyle(rate float64) lipgloss.Style {
+	switch {
+	case rate >= 0.75:
+		return s.Checked
+	case rate >= 0.55:
+		return s.Warning
+	default:
+		return s.Error
+	}
+}
+
+func (s Styles) successBar(rate float64, width int) string {
+	filled := int(rate*float64(width) + 0.5)
+	if filled > width {
+		filled = width
+	}
+	full, empty := "█", "░"
+	if s.ascii {
+		full, empty = "#", "-"
+	}
+	return strings.Repeat(full, filled) + strings.Repeat(empty, width-filled)
+}
+
+func formatKB(bytes int) string {
+	if bytes%1000 == 0 {
+		return fmt.Sprintf("%d", bytes/1000)
+	}
+	return fmt.Sprintf("%.1f", float64(bytes)/1000)
+}
+
+func formatStars(n int) string {
+	if n >= 1000 {
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func (s Styles) joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return s.ItemDescInline.Render("none")
+	}
+	return strings.Join(items, ", ")
+}
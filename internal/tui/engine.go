@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// --- Step 6: Engine ---
+
+// engineField is a focusable settings field on the engine step.
+type engineField struct {
+	label string
+	input *textinput.Model
+}
+
+// engineFields returns the settings fields that apply to the selected
+// engine, in focus order.
+func (m *Model) engineFields() []engineField {
+	engine := data.Engines[m.engineCursor]
+	var fields []engineField
+	if !engine.Custom {
+		fields = append(fields, engineField{"Model", &m.modelInput})
+	}
+	if engine.MaxTurns {
+		fields = append(fields, engineField{"Max turns", &m.maxTurnsInput})
+	}
+	if engine.Custom {
+		fields = append(fields, engineField{"Command", &m.commandInput})
+	}
+	return fields
+}
+
+// focusEngineField focuses a settings field; 0 is the engine list and
+// fields are numbered from 1.
+func (m *Model) focusEngineField(i int) tea.Cmd {
+	var cmd tea.Cmd
+	for j, f := range m.engineFields() {
+		if j+1 == i {
+			cmd = f.input.Focus()
+		} else {
+			f.input.Blur()
+		}
+	}
+	m.engineFocus = i
+	return cmd
+}
+
+func (m *Model) updateEngineField(msg tea.Msg) tea.Cmd {
+	if m.engineFocus == 0 {
+		return nil
+	}
+	f := m.engineFields()[m.engineFocus-1]
+	var cmd tea.Cmd
+	*f.input, cmd = f.input.Update(msg)
+	return cmd
+}
+
+func (m Model) updateEngine(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	fields := len(m.engineFields())
+	switch {
+	case m.press(msg, m.keys.NextField):
+		return m, m.focusEngineField((m.engineFocus + 1) % (fields + 1))
+	case m.press(msg, m.keys.PrevField):
+		return m, m.focusEngineField((m.engineFocus + fields) % (fields + 1))
+	case m.press(msg, m.keys.Up):
+		if m.engineFocus == 0 {
+			if m.engineCursor > 0 {
+				m.engineCursor--
+			}
+			return m, nil
+		}
+		return m, m.focusEngineField(m.engineFocus - 1)
+	case m.press(msg, m.keys.Down):
+		if m.engineFocus == 0 {
+			if m.engineCursor < len(data.Engines)-1 {
+				m.engineCursor++
+			}
+			return m, nil
+		}
+		if m.engineFocus < fields {
+			return m, m.focusEngineField(m.engineFocus + 1)
+		}
+		return m, nil
+	case m.press(msg, m.keys.Select):
+		if m.engineErr() != nil {
+			return m, nil
+		}
+		m.focusEngineField(0)
+		m.step = stepNetwork
+		return m, m.moveNetworkCursor(m.networkCursor)
+	}
+	return m, m.updateEngineField(msg)
+}
+
+// applyEngineConfig fills the engine form, selecting the default engine
+// when cfg has none.
+func (m *Model) applyEngineConfig(cfg generator.EngineConfig) {
+	engine := cfg.ID
+	if engine == "" {
+		engine = data.DefaultEngine
+	}
+	for i, e := range data.Engines {
+		if e.ID == engine {
+			m.engineCursor = i
+		}
+	}
+	m.modelInput.SetValue(cfg.Model)
+	m.maxTurnsInput.SetValue("")
+	if cfg.MaxTurns > 0 {
+		m.maxTurnsInput.SetValue(strconv.Itoa(cfg.MaxTurns))
+	}
+	m.commandInput.SetValue(cfg.Command)
+}
+
+// engineConfig reads the engine settings from the form, ignoring fields
+// that don't apply to the selected engine.
+func (m Model) engineConfig() (generator.EngineConfig, error) {
+	engine := data.Engines[m.engineCursor]
+	cfg := generator.EngineConfig{ID: engine.ID}
+	if !engine.Custom {
+		cfg.Model = strings.TrimSpace(m.modelInput.Value())
+		if cfg.Model == "" && m.patterns.ConfigDefaults.Model != nil {
+			cfg.Model = *m.patterns.ConfigDefaults.Model
+		}
+	}
+	if engine.MaxTurns {
+		if v := strings.TrimSpace(m.maxTurnsInput.Value()); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return cfg, fmt.Errorf("max turns must be a positive number")
+			}
+			cfg.MaxTurns = n
+		}
+	}
+	if engine.Custom {
+		cfg.Command = strings.TrimSpace(m.commandInput.Value())
+	}
+	return cfg, cfg.Validate()
+}
+
+func (m Model) engineErr() error {
+	_, err := m.engineConfig()
+	return err
+}
+
+// engineSecretStep describes the secret setup next step for the selected
+// engine.
+func (m Model) engineSecretStep() string {
+	engine := data.Engines[m.engineCursor]
+	if engine.Secret == "" {
+		return "Set up any secrets your agent command needs"
+	}
+	return fmt.Sprintf("Set up the %s secret for the %s engine", engine.Secret, engine.Label)
+}
+
+func (m Model) viewEngine() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Render("AI engine"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.Subtitle.Render("Pick the engine that runs the agent — each one needs its own secret"))
+	b.WriteString("\n\n")
+
+	for i, e := range data.Engines {
+		cursor := "  "
+		if i == m.engineCursor && m.engineFocus == 0 {
+			cursor = m.styles.Cursor + " "
+		}
+		radio := m.styles.Unchecked.Render(m.styles.RadioOff)
+		nameStyle := m.styles.UnselectedItem
+		if i == m.engineCursor {
+			radio = m.styles.Checked.Render(m.styles.RadioOn)
+			nameStyle = m.styles.SelectedItem
+		}
+		desc := e.Description
+		if e.Secret != "" {
+			desc += " · needs " + e.Secret
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s  %s\n",
+			cursor, radio, nameStyle.Width(8).Render(e.Label), m.styles.ItemDescInline.Render(desc)))
+	}
+	b.WriteString("\n")
+
+	m.modelInput.Placeholder = "engine default"
+	if model := m.patterns.ConfigDefaults.Model; model != nil && *model != "" {
+		m.modelInput.Placeholder = *model
+	}
+	for _, f := range m.engineFields() {
+		b.WriteString(fmt.Sprintf("  %s %s\n", m.styles.DetailLabel.Render(f.label+":"), f.input.View()))
+	}
+	if err := m.engineErr(); err != nil {
+		b.WriteString("\n  " + m.styles.Error.Render(err.Error()) + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
@@ -0,0 +1,261 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sahilm/fuzzy"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+// The archetype and trigger lists share a fuzzy filter opened with /,
+// paging when the list is taller than the terminal, and number keys that
+// jump to the items on the current page. Cursors keep indexing the full
+// catalog so the rest of the wizard doesn't need to know about filtering.
+
+// listFilter narrows a list to the items whose fields fuzzy-match a query.
+type listFilter struct {
+	input  textinput.Model
+	typing bool
+	// matches holds the matching item indexes in catalog order; nil when
+	// there is no query.
+	matches []int
+}
+
+// maxMatchSpread bounds how far apart, per query character, the matched
+// characters of a fuzzy match may be.
+const maxMatchSpread = 3
+
+func newListFilter() listFilter {
+	fi := textinput.New()
+	fi.Prompt = "/"
+	fi.Placeholder = "filter"
+	fi.CharLimit = 60
+	fi.Width = 30
+	return listFilter{input: fi}
+}
+
+func (f listFilter) query() string { return strings.TrimSpace(f.input.Value()) }
+
+// active reports whether the filter is being typed or narrows the list.
+func (f listFilter) active() bool { return f.typing || f.query() != "" }
+
+// visible returns the indexes of the items to show out of n.
+func (f listFilter) visible(n int) []int {
+	if f.query() == "" {
+		all := make([]int, n)
+		for i := range all {
+			all[i] = i
+		}
+		return all
+	}
+	return f.matches
+}
+
+// apply recomputes the matches; fields holds the searchable text of each
+// item.
+func (f *listFilter) apply(fields [][]string) {
+	f.matches = nil
+	q := f.query()
+	if q == "" {
+		return
+	}
+	var source []string
+	var owner []int
+	for i, item := range fields {
+		for _, s := range item {
+			source = append(source, s)
+			owner = append(owner, i)
+		}
+	}
+	for _, match := range fuzzy.Find(q, source) {
+		// Any short query is a subsequence of a long tip, so only count
+		// matches whose characters sit close together.
+		idx := match.MatchedIndexes
+		if idx[len(idx)-1]-idx[0] >= len(q)*maxMatchSpread {
+			continue
+		}
+		if i := owner[match.Index]; !slices.Contains(f.matches, i) {
+			f.matches = append(f.matches, i)
+		}
+	}
+	slices.Sort(f.matches)
+}
+
+func (f *listFilter) open() tea.Cmd {
+	f.typing = true
+	return f.input.Focus()
+}
+
+// close stops typing, clearing the query when clear is set. It reports
+// whether the filter was active.
+func (f *listFilter) close(clear bool) bool {
+	was := f.active()
+	f.typing = false
+	f.input.Blur()
+	if clear {
+		f.input.SetValue("")
+		f.matches = nil
+	}
+	return was
+}
+
+// update feeds a key to the filter input while typing. Select keeps the
+// query and returns to the list.
+func (f *listFilter) update(msg tea.KeyMsg, keys KeyMap, fields [][]string) tea.Cmd {
+	if key.Matches(msg, keys.Select) {
+		f.close(false)
+		return nil
+	}
+	var cmd tea.Cmd
+	f.input, cmd = f.input.Update(msg)
+	f.apply(fields)
+	return cmd
+}
+
+func (f listFilter) view(s Styles, shown, total int) string {
+	if !f.active() {
+		return ""
+	}
+	input := s.ItemDescInline.Render("/" + f.query())
+	if f.typing {
+		input = f.input.View()
+	}
+	count := fmt.Sprintf("%d of %d", shown, total)
+	if shown == 0 {
+		return "  " + input + "  " + s.Error.Render("no matches") + "\n\n"
+	}
+	return "  " + input + "  " + s.ItemDescInline.Render(count) + "\n\n"
+}
+
+// listPos returns the cursor's position within visible, or 0 when the
+// cursor is filtered out.
+func listPos(visible []int, cursor int) int {
+	return max(slices.Index(visible, cursor), 0)
+}
+
+// moveList moves the cursor by delta within the visible items, stopping at
+// either end.
+func moveList(visible []int, cursor, delta int) int {
+	if len(visible) == 0 {
+		return cursor
+	}
+	pos := min(max(listPos(visible, cursor)+delta, 0), len(visible)-1)
+	return visible[pos]
+}
+
+// listPage returns the bounds of the page of visible holding the cursor.
+func listPage(visible []int, cursor, perPage int) (start, end int) {
+	if perPage <= 0 || len(visible) <= perPage {
+		return 0, len(visible)
+	}
+	start = listPos(visible, cursor) / perPage * perPage
+	return start, min(start+perPage, len(visible))
+}
+
+// updateList handles the navigation keys shared by filterable lists. It
+// returns the new cursor and whether the key was handled.
+func (m Model) updateList(msg tea.KeyMsg, visible []int, cursor, perPage int) (int, bool) {
+	switch {
+	case m.press(msg, m.keys.Up):
+		return moveList(visible, cursor, -1), true
+	case m.press(msg, m.keys.Down):
+		return moveList(visible, cursor, 1), true
+	case m.press(msg, m.keys.PageUp):
+		return moveList(visible, cursor, -max(perPage, 1)), true
+	case m.press(msg, m.keys.PageDown):
+		return moveList(visible, cursor, max(perPage, 1)), true
+	case m.press(msg, m.keys.Top):
+		return moveList(visible, cursor, -len(visible)), true
+	case m.press(msg, m.keys.Bottom):
+		return moveList(visible, cursor, len(visible)), true
+	}
+	if key := msg.String(); len(key) == 1 && key[0] >= '1' && key[0] <= '9' {
+		start, end := listPage(visible, cursor, perPage)
+		if i := start + int(key[0]-'1'); i < end {
+			return visible[i], true
+		}
+		return cursor, true
+	}
+	return cursor, false
+}
+
+// listNumber labels the items on a page for the number keys.
+func (s Styles) listNumber(pos, start int) string {
+	if n := pos - start + 1; n <= 9 {
+		return s.ItemDescInline.Render(fmt.Sprint(n)) + " "
+	}
+	return "  "
+}
+
+// viewPageInfo shows which page of the list is visible.
+func (s Styles) viewPageInfo(visible []int, cursor, perPage int) string {
+	if perPage <= 0 || len(visible) <= perPage {
+		return ""
+	}
+	pages := (len(visible) + perPage - 1) / perPage
+	page := listPos(visible, cursor)/perPage + 1
+	return "  " + s.ItemDescInline.Render(fmt.Sprintf("page %d of %d", page, pages)) + "\n"
+}
+
+// listChrome is the number of rows around a list that aren't list items:
+// app padding, the progress bar, title, subtitle, filter, page info and
+// help line.
+const listChrome = 2 + 2 + 2 + 2 + 2 + 1 + 2
+
+// closeFilter clears the filter on the current step's list, reporting
+// whether there was one.
+func (m *Model) closeFilter() bool {
+	switch m.step {
+	case stepArchetype:
+		return m.archFilter.close(true)
+	case stepTriggers:
+		return m.triggerFilter.close(true)
+	}
+	return false
+}
+
+func (m Model) archetypeFields() [][]string {
+	fields := make([][]string, len(m.patterns.Archetypes))
+	for i, a := range m.patterns.Archetypes {
+		fields[i] = append([]string{a.Label, a.ID, a.Description}, a.Tips...)
+	}
+	return fields
+}
+
+func (m Model) archVisible() []int { return m.archFilter.visible(len(m.patterns.Archetypes)) }
+
+// archPerPage returns how many archetypes fit on screen, each taking two
+// rows, or 0 before the terminal size is known.
+func (m Model) archPerPage() int {
+	if m.height == 0 {
+		return 0
+	}
+	return max((m.height-listChrome)/2, 3)
+}
+
+func (m Model) triggerFields() [][]string {
+	fields := make([][]string, len(m.patterns.Triggers))
+	for i, t := range m.patterns.Triggers {
+		fields[i] = []string{t.ID, t.Event, t.Description}
+	}
+	return fields
+}
+
+func (m Model) triggerVisible() []int { return m.triggerFilter.visible(len(m.patterns.Triggers)) }
+
+// triggerPerPage returns how many triggers fit beside the tips and
+// warnings around the list, or 0 before the terminal size is known.
+func (m Model) triggerPerPage() int {
+	if m.height == 0 {
+		return 0
+	}
+	extra := m.renderTips(data.TipTriggers) + m.renderTriggerWarnings() + m.renderComboFeedback()
+	// Two more rows for the modifiers heading.
+	return max(m.height-listChrome-strings.Count(extra, "\n")-2, 3)
+}
@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stepKeys returns the keys that do something on the current step, in the
+// order the footer lists them.
+func (m Model) stepKeys() []helpEntry {
+	k := m.keys
+	nav := hk("navigate", k.Up, k.Down)
+	jump := helpEntry{label: "1-9", desc: "jump"}
+	back := hk("back", k.Back)
+
+	switch m.step {
+	case stepArchetype:
+		if m.archFilter.typing {
+			return []helpEntry{hk("keep filter", k.Select), hk("clear filter", k.Back)}
+		}
+		return []helpEntry{nav, hk("filter", k.Filter), jump, hk("select", k.Select),
+			hk("undo/redo", k.Undo, k.Redo), hk("quit", k.Quit)}
+	case stepTriggers:
+		if m.triggerFilter.typing {
+			return []helpEntry{hk("keep filter", k.Select), hk("clear filter", k.Back)}
+		}
+		return []helpEntry{nav, hk("filter", k.Filter), jump, hk("toggle", k.Toggle), hk("next", k.Select),
+			hk("dismiss tips", k.DismissTips), back}
+	case stepContext:
+		return []helpEntry{hk("switch field", k.Up, k.Down), hk("cycle memory", k.CycleMemory),
+			hk("toggle import", k.Toggle), hk("next", k.Select), hk("dismiss tips", k.DismissTips), back}
+	case stepTools:
+		switch m.mcpMode {
+		case mcpCatalog:
+			return []helpEntry{nav, hk("choose", k.Select), hk("cancel", k.Back)}
+		case mcpForm:
+			return []helpEntry{hk("switch field", k.Up, k.Down), hk("save", k.Select), hk("cancel", k.Back)}
+		}
+		return []helpEntry{nav, hk("toggle/cycle/remove", k.Toggle), hk("add server", k.Add),
+			hk("edit server", k.Edit), hk("reset permissions", k.Reset), hk("next", k.Select), back}
+	case stepPreSteps:
+		return []helpEntry{nav, hk("toggle", k.Toggle), hk("edit parameters", k.NextField),
+			hk("next", k.Select), hk("dismiss tips", k.DismissTips), back}
+	case stepEngine:
+		return []helpEntry{hk("select engine", k.Up, k.Down), hk("next field", k.NextField),
+			hk("next", k.Select), back}
+	case stepNetwork:
+		return []helpEntry{nav, hk("toggle/remove", k.Toggle), hk("add domain or generate", k.Select), back}
+	case stepPreview:
+		if m.written {
+			return []helpEntry{hk("quit", k.Quit)}
+		}
+		if m.searching {
+			return []helpEntry{hk("keep search", k.Select), hk("clear search", k.Back)}
+		}
+		render := "rendered prompt"
+		if m.previewRendered {
+			render = "raw file"
+		}
+		return []helpEntry{hk("scroll", k.Up, k.Down), hk("page", k.PageUp, k.PageDown),
+			hk("search", k.Filter), hk("next/prev match", k.NextMatch, k.PrevMatch), hk(render, k.Render),
+			hk("write to "+m.outputDir+"/", k.Write), hk("undo", k.Undo),
+			hk("dismiss tips", k.DismissTips), back, hk("quit", k.Quit)}
+	}
+	return nil
+}
+
+// globalKeys returns the keys that work on every step.
+func (m Model) globalKeys() []helpEntry {
+	k := m.keys
+	return []helpEntry{
+		hk("undo the last change", k.Undo),
+		hk("redo", k.Redo),
+		hk("toggle the live preview", k.LivePreview),
+		hk("dismiss tips on this step", k.DismissTips),
+		hk("go back / cancel", k.Back),
+		hk("quit (ctrl+c always works)", k.Quit),
+		hk("show / hide this help", k.Help),
+	}
+}
+
+// viewHelp renders the help overlay: every key for the current step, then
+// the keys that work everywhere. Letter keys don't fire while a text field
+// has focus.
+func (m Model) viewHelp() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Render("Keys — " + stepLabels[m.step]))
+	b.WriteString("\n")
+
+	steps, global := m.stepKeys(), m.globalKeys()
+	width := 0
+	for _, e := range append(slices.Clone(steps), global...) {
+		width = max(width, lipgloss.Width(e.full(m.styles.ascii)))
+	}
+	section := func(title string, entries []helpEntry) {
+		b.WriteString("  " + m.styles.DetailHeading.Render(title) + "\n")
+		for _, e := range entries {
+			b.WriteString("    " + m.styles.SelectedItem.Width(width+2).Render(e.full(m.styles.ascii)) + m.styles.ItemDescInline.Render(e.desc) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	section("This step", steps)
+	section("Everywhere", global)
+
+	b.WriteString(m.styles.ItemDescInline.Render("  Letter keys type into text fields instead while one has focus."))
+	b.WriteString("\n")
+	b.WriteString(m.styles.Help.Render("any key to close"))
+	return b.String()
+}
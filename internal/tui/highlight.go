@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Highlighting colors the workflow line by line without changing a single
+// character, so the highlighted view is exactly what gets written.
+
+var (
+	yamlKeyRe     = regexp.MustCompile(`^(\s*)(- )?([A-Za-z0-9_.-]+)(:)(.*)$`)
+	yamlItemRe    = regexp.MustCompile(`^(\s*)(- )(.*)$`)
+	yamlNumberRe  = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	mdHeadingRe   = regexp.MustCompile(`^#{1,6} `)
+	mdListRe      = regexp.MustCompile(`^(\s*)([-*]|\d+\.)( .*)$`)
+	mdCodeSpanRe  = regexp.MustCompile("`[^`]+`")
+	mdBoldRe      = regexp.MustCompile(`\*\*[^*]+\*\*`)
+	yamlExprRe    = regexp.MustCompile(`\$\{\{[^}]*\}\}`)
+	mdFenceMarker = "```"
+)
+
+// highlightLines returns the workflow's lines with YAML frontmatter and
+// markdown syntax colored.
+func (s Styles) highlightLines(text string) []string {
+	lines := strings.Split(text, "\n")
+	out := make([]string, len(lines))
+	inFrontmatter, inFence := false, false
+	for i, line := range lines {
+		switch {
+		case line == "---" && (i == 0 || inFrontmatter):
+			inFrontmatter = i == 0
+			out[i] = s.YAMLPunct.Render(line)
+		case inFrontmatter:
+			out[i] = s.highlightYAML(line)
+		case strings.HasPrefix(line, mdFenceMarker):
+			inFence = !inFence
+			out[i] = s.MDCode.Render(line)
+		case inFence:
+			out[i] = s.MDCode.Render(line)
+		default:
+			out[i] = s.highlightMarkdown(line)
+		}
+	}
+	return out
+}
+
+func (s Styles) highlightYAML(line string) string {
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return s.YAMLComment.Render(line)
+	}
+	if m := yamlKeyRe.FindStringSubmatch(line); m != nil {
+		return m[1] + s.YAMLPunct.Render(m[2]) + s.YAMLKey.Render(m[3]) + s.YAMLPunct.Render(m[4]) + s.highlightYAMLValue(m[5])
+	}
+	if m := yamlItemRe.FindStringSubmatch(line); m != nil {
+		return m[1] + s.YAMLPunct.Render(m[2]) + s.highlightYAMLValue(m[3])
+	}
+	return s.highlightYAMLValue(line)
+}
+
+// highlightYAMLValue colors a scalar or flow sequence, keeping leading
+// whitespace.
+func (s Styles) highlightYAMLValue(v string) string {
+	trimmed := strings.TrimLeft(v, " ")
+	lead := v[:len(v)-len(trimmed)]
+	switch {
+	case trimmed == "":
+		return v
+	case strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{"):
+		var b strings.Builder
+		for _, part := range splitKeep(trimmed, "[]{},") {
+			if len(part) == 1 && strings.Contains("[]{},", part) {
+				b.WriteString(s.YAMLPunct.Render(part))
+			} else {
+				b.WriteString(s.highlightYAMLValue(part))
+			}
+		}
+		return lead + b.String()
+	case strings.HasPrefix(trimmed, `"`) || strings.HasPrefix(trimmed, "'"):
+		return lead + s.highlightExprs(trimmed, s.YAMLString)
+	case yamlNumberRe.MatchString(trimmed), trimmed == "true", trimmed == "false", trimmed == "null":
+		return lead + s.YAMLNumber.Render(trimmed)
+	case trimmed == "|":
+		return lead + s.YAMLPunct.Render(trimmed)
+	}
+	return lead + s.highlightExprs(trimmed, s.YAMLValue)
+}
+
+// highlightExprs renders v in style with ${{ }} expressions set apart.
+func (s Styles) highlightExprs(v string, style lipgloss.Style) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range yamlExprRe.FindAllStringIndex(v, -1) {
+		b.WriteString(style.Render(v[last:loc[0]]))
+		b.WriteString(s.YAMLExpr.Render(v[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(style.Render(v[last:]))
+	return b.String()
+}
+
+// splitKeep splits s around any of the separator bytes, keeping each
+// separator as its own element.
+func splitKeep(s, seps string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(seps, s[i]) >= 0 {
+			if i > start {
+				parts = append(parts, s[start:i])
+			}
+			parts = append(parts, s[i:i+1])
+			start = i + 1
+		}
+	}
+	if start < len(s) {
+		parts = append(parts, s[start:])
+	}
+	return parts
+}
+
+func (s Styles) highlightMarkdown(line string) string {
+	if mdHeadingRe.MatchString(line) {
+		return s.MDHeading.Render(line)
+	}
+	if m := mdListRe.FindStringSubmatch(line); m != nil {
+		return m[1] + s.MDListMarker.Render(m[2]) + s.highlightInline(m[3])
+	}
+	return s.highlightInline(line)
+}
+
+// highlightInline colors code spans and bold text, leaving the markers in
+// place.
+func (s Styles) highlightInline(line string) string {
+	line = mdCodeSpanRe.ReplaceAllStringFunc(line, func(c string) string { return s.MDCode.Render(c) })
+	return mdBoldRe.ReplaceAllStringFunc(line, func(c string) string { return s.MDBold.Render(c) })
+}
+
+// renderMarkdown renders the prompt body for reading: headings without
+// their markers, bullets, and code spans without backticks.
+func (s Styles) renderMarkdown(body string) []string {
+	var out []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		switch {
+		case strings.HasPrefix(line, mdFenceMarker):
+			inFence = !inFence
+		case inFence:
+			out = append(out, "    "+s.MDCode.Render(line))
+		case mdHeadingRe.MatchString(line):
+			text := strings.TrimLeft(line, "#")
+			out = append(out, s.MDHeading.Render(strings.TrimSpace(text)))
+		default:
+			if m := mdListRe.FindStringSubmatch(line); m != nil {
+				marker := s.Bullet
+				if m[2] != "-" && m[2] != "*" {
+					marker = m[2]
+				}
+				line = m[1] + s.MDListMarker.Render(marker) + m[3]
+			}
+			line = mdCodeSpanRe.ReplaceAllStringFunc(line, func(c string) string { return s.MDCode.Render(strings.Trim(c, "`")) })
+			line = mdBoldRe.ReplaceAllStringFunc(line, func(c string) string { return s.MDBold.Render(strings.Trim(c, "*")) })
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// promptBody returns the markdown after the frontmatter.
+func promptBody(workflow string) string {
+	if rest, ok := strings.CutPrefix(workflow, "---\n"); ok {
+		if _, body, ok := strings.Cut(rest, "\n---\n"); ok {
+			return strings.TrimLeft(body, "\n")
+		}
+	}
+	return workflow
+}
@@ -0,0 +1,90 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// tipStep maps a wizard step to the tips shown on it.
+func (m Model) tipStep() (data.TipStep, bool) {
+	switch m.step {
+	case stepTriggers:
+		return data.TipTriggers, true
+	case stepContext:
+		return data.TipContext, true
+	case stepPreSteps:
+		return data.TipPreSteps, true
+	case stepNetwork:
+		return data.TipNetwork, true
+	case stepPreview:
+		return data.TipPreview, true
+	}
+	return data.TipGeneral, false
+}
+
+func (m Model) tipState() data.TipState {
+	cfg := m.workflowConfig()
+	stats := generator.Analyze(cfg)
+	return data.TipState{
+		Triggers:    data.TriggerEvents(cfg.Triggers),
+		PromptBytes: stats.PromptBytes,
+		HasPreSteps: stats.HasPreSteps,
+		HasDoNot:    stats.HasDoNot,
+		HasNetwork:  stats.HasNetwork,
+	}
+}
+
+// activeTips returns the archetype's undismissed tips for a step.
+func (m Model) activeTips(step data.TipStep) []data.TipRule {
+	var rules []data.TipRule
+	for _, r := range m.patterns.Archetypes[m.archCursor].TipRules() {
+		if r.Step == step && !m.dismissedTips[r.Tip] {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+func (m *Model) dismissTips() {
+	step, ok := m.tipStep()
+	if !ok {
+		return
+	}
+	for _, r := range m.activeTips(step) {
+		m.dismissedTips[r.Tip] = true
+	}
+}
+
+// renderTips renders the tips for a step, flagging the ones the current
+// choices contradict.
+func (m Model) renderTips(step data.TipStep) string {
+	rules := m.activeTips(step)
+	if len(rules) == 0 {
+		return ""
+	}
+
+	state := m.tipState()
+	var b strings.Builder
+	for _, r := range rules {
+		if r.Contradicts != nil && r.Contradicts(state) {
+			b.WriteString(m.renderWarning(m.styles.Warning, r.Tip))
+		} else {
+			b.WriteString("  " + m.styles.Tip.Render(m.styles.TipIcon+" "+r.Tip) + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderWarning renders an indented warning line wrapped to the terminal
+// width.
+func (m Model) renderWarning(style lipgloss.Style, text string) string {
+	if m.width > 12 {
+		style = style.Width(m.width - 8)
+	}
+	return lipgloss.NewStyle().PaddingLeft(2).Render(style.Render(m.styles.WarnIcon+" "+text)) + "\n"
+}
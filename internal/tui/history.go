@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// maxHistory bounds the undo stack.
+const maxHistory = 100
+
+// textFields are the State fields edited by typing. Consecutive edits to
+// the same one are undone together.
+var textFields = []string{"Name", "Context", "Timeout", "MemoryKey", "Retention", "Model", "MaxTurns", "Command"}
+
+// resetFields are the State fields that choosing a different archetype
+// resets to its defaults.
+var resetFields = []string{"Triggers", "Toolsets", "Permissions", "PreSteps", "PreStepParams",
+	"NetworkEcosystems", "NetworkDeny", "NetworkDomains"}
+
+// changedFields returns the names of the State fields that differ between
+// a and b, ignoring the step.
+func changedFields(a, b State) []string {
+	a.Step, b.Step = "", ""
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	var names []string
+	for i := range va.NumField() {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			names = append(names, va.Type().Field(i).Name)
+		}
+	}
+	return names
+}
+
+// record pushes the state from before a key press onto the undo stack if
+// the key changed any choice.
+func (m *Model) record(before State) {
+	// Picking the first archetype isn't undoable: there is nothing to go
+	// back to.
+	if before.Archetype == "" {
+		return
+	}
+	changed := changedFields(before, m.State())
+	if len(changed) == 0 {
+		return
+	}
+	edit := ""
+	if len(changed) == 1 && slices.Contains(textFields, changed[0]) {
+		edit = changed[0]
+	}
+	if edit == "" || edit != m.lastEdit {
+		m.undoStack = append(m.undoStack, before)
+		if len(m.undoStack) > maxHistory {
+			m.undoStack = slices.Delete(m.undoStack, 0, 1)
+		}
+	}
+	m.lastEdit = edit
+	m.redoStack = nil
+}
+
+func (m *Model) undo() tea.Cmd {
+	if len(m.undoStack) == 0 {
+		return nil
+	}
+	prev := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.redoStack = append(m.redoStack, m.State())
+	return m.restore(prev)
+}
+
+func (m *Model) redo() tea.Cmd {
+	if len(m.redoStack) == 0 {
+		return nil
+	}
+	next := m.redoStack[len(m.redoStack)-1]
+	m.redoStack = m.redoStack[:len(m.redoStack)-1]
+	m.undoStack = append(m.undoStack, m.State())
+	return m.restore(next)
+}
+
+// restore applies a snapshot while staying on the current step.
+func (m *Model) restore(s State) tea.Cmd {
+	s.Step = stepLabels[m.step]
+	m.applyState(s)
+	m.lastEdit = ""
+	m.confirmDiscard = false
+	if m.step == stepContext {
+		return m.focusContextField(fieldContext)
+	}
+	return nil
+}
+
+// defaultState returns the choices a fresh session would have for the
+// applied archetype.
+func (m Model) defaultState() State {
+	d := m
+	d.archCursor = m.appliedArch
+	d.resetForArchetype()
+	for _, f := range d.contextFields() {
+		f.SetValue("")
+	}
+	d.contextInput.SetValue(m.defaultContext)
+	d.memoryKind = m.defaultMemory
+	d.memoryKeyInput.SetValue("")
+	d.retentionInput.SetValue("")
+	d.imports = nil
+	d.shareContext = false
+	d.mcpServers = nil
+	d.applyEngineConfig(m.engineDefault)
+	d.dismissedTips = nil
+	return d.State()
+}
+
+// customizations returns the choices that differ from the applied
+// archetype's defaults and would be lost by switching archetype.
+func (m Model) customizations() []string {
+	if m.appliedArch < 0 {
+		return nil
+	}
+	var fields []string
+	for _, f := range changedFields(m.defaultState(), m.State()) {
+		if slices.Contains(resetFields, f) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// discardWarning explains what switching archetype would discard.
+func (m Model) discardWarning() string {
+	labels := map[string]string{
+		"Triggers": "triggers", "Toolsets": "tools", "Permissions": "permissions",
+		"PreSteps": "data steps", "PreStepParams": "data steps",
+		"NetworkEcosystems": "network", "NetworkDeny": "network", "NetworkDomains": "network",
+	}
+	var lost []string
+	for _, f := range m.customizations() {
+		if l := labels[f]; !slices.Contains(lost, l) {
+			lost = append(lost, l)
+		}
+	}
+	return fmt.Sprintf("Switching to %s resets your %s choices for %s — press %s again to switch (%s undoes it)",
+		m.patterns.Archetypes[m.archCursor].Label, strings.Join(lost, ", "), m.patterns.Archetypes[m.appliedArch].Label,
+		m.keyName(m.keys.Select), m.keyName(m.keys.Undo))
+}
+
+// changesFromDefaults describes each choice that differs from the
+// archetype's defaults, for the preview summary.
+func (m Model) changesFromDefaults() []string {
+	if m.appliedArch < 0 {
+		return nil
+	}
+	def, cur := m.defaultState(), m.State()
+	var changes []string
+	for _, f := range changedFields(def, cur) {
+		switch f {
+		case "Triggers":
+			changes = append(changes, "triggers "+diffList(def.Triggers, cur.Triggers))
+		case "Name":
+			changes = append(changes, "name "+cur.Name)
+		case "Context":
+			changes = append(changes, "project context added")
+		case "Timeout":
+			changes = append(changes, fmt.Sprintf("timeout %s min", cur.Timeout))
+		case "MemoryKind":
+			changes = append(changes, "memory "+orNone(cur.MemoryKind))
+		case "MemoryKey":
+			changes = append(changes, "memory key "+cur.MemoryKey)
+		case "Retention":
+			changes = append(changes, fmt.Sprintf("retention %s days", cur.Retention))
+		case "Imports":
+			changes = append(changes, "imports "+diffList(def.Imports, cur.Imports))
+		case "ShareContext":
+			changes = append(changes, "project context shared as a fragment")
+		case "Toolsets":
+			changes = append(changes, "toolsets "+orNone(strings.Join(cur.Toolsets, ", ")))
+		case "Permissions":
+			var scopes []string
+			for scope, level := range cur.Permissions {
+				scopes = append(scopes, scope+": "+level)
+			}
+			sort.Strings(scopes)
+			changes = append(changes, "permissions "+strings.Join(scopes, ", "))
+		case "MCPServers":
+			var names []string
+			for _, s := range cur.MCPServers {
+				names = append(names, s.Name)
+			}
+			changes = append(changes, "MCP servers "+orNone(strings.Join(names, ", ")))
+		case "PreSteps":
+			changes = append(changes, "pre-steps "+diffList(m.preStepIDsFor(def), m.preStepIDsFor(cur)))
+		case "PreStepParams":
+			changes = append(changes, "pre-step parameters edited")
+		case "Engine", "Model", "MaxTurns", "Command":
+			if !slices.Contains(changes, "engine") {
+				changes = append(changes, "engine")
+			}
+		case "NetworkEcosystems":
+			changes = append(changes, "network "+diffList(def.NetworkEcosystems, cur.NetworkEcosystems))
+		case "NetworkDeny":
+			changes = append(changes, "default-deny network")
+		case "NetworkDomains":
+			changes = append(changes, "domains "+orNone(strings.Join(cur.NetworkDomains, ", ")))
+		}
+	}
+	// Describe the engine once, with its settings.
+	if i := slices.Index(changes, "engine"); i >= 0 {
+		engine, _ := m.engineConfig()
+		desc := "engine " + engine.ID
+		if engine.Model != "" {
+			desc += " (" + engine.Model + ")"
+		}
+		changes[i] = desc
+	}
+	return changes
+}
+
+// preStepIDsFor resolves a state's pre-steps, which are null while the
+// archetype's defaults apply.
+func (m Model) preStepIDsFor(s State) []string {
+	if s.PreSteps != nil {
+		return s.PreSteps
+	}
+	var ids []string
+	for _, step := range generator.DefaultPreSteps(s.Archetype) {
+		ids = append(ids, step.Recipe.ID)
+	}
+	return ids
+}
+
+// diffList renders the items added to and removed from a list, e.g.
+// "+issue_comment -schedule".
+func diffList(before, after []string) string {
+	var parts []string
+	for _, a := range after {
+		if !slices.Contains(before, a) {
+			parts = append(parts, "+"+a)
+		}
+	}
+	for _, b := range before {
+		if !slices.Contains(after, b) {
+			parts = append(parts, "-"+b)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// renderChanges shows the changes from the archetype's defaults as one
+// wrapped line.
+func (m Model) renderChanges() string {
+	changes := m.changesFromDefaults()
+	if len(changes) == 0 {
+		return "  " + m.styles.ItemDescInline.Render("Using the archetype's defaults") + "\n\n"
+	}
+	style := m.styles.ItemDescInline
+	if m.width > 8 {
+		style = style.Width(m.width - 8)
+	}
+	return "  " + style.Render("Changes from defaults: "+strings.Join(changes, " · ")) + "\n\n"
+}
@@ -0,0 +1,160 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ashleywolf/gh-aw-create/internal/fragment"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// Shared fragments are listed on the context step below the text fields,
+// as rows the focus moves onto: one per fragment found in the repository,
+// then one that saves the project context as a fragment of its own.
+
+// fragmentRow returns the focused fragment row: an index into m.fragments,
+// len(m.fragments) for the save-context row, or -1 while a text field has
+// focus.
+func (m Model) fragmentRow() int {
+	fields := len(m.contextFields())
+	if m.contextFocus < fields {
+		return -1
+	}
+	return m.contextFocus - fields
+}
+
+// contextRows is the number of focusable rows on the context step.
+func (m Model) contextRows() int {
+	return len(m.contextFields()) + len(m.fragments) + 1
+}
+
+// toggleFragmentRow imports or drops the focused fragment, or toggles
+// saving the project context as one.
+func (m *Model) toggleFragmentRow() {
+	row := m.fragmentRow()
+	if row < 0 {
+		return
+	}
+	if row == len(m.fragments) {
+		m.shareContext = !m.shareContext
+		return
+	}
+	p := m.fragments[row].Path
+	if i := slices.Index(m.imports, p); i >= 0 {
+		m.imports = slices.Delete(slices.Clone(m.imports), i, i+1)
+	} else {
+		m.imports = append(slices.Clone(m.imports), p)
+	}
+}
+
+// sharingContext reports whether the project context goes into its own
+// fragment rather than the prompt.
+func (m Model) sharingContext() bool {
+	return m.shareContext && strings.TrimSpace(m.contextInput.Value()) != ""
+}
+
+// importedFragments returns the fragments the workflow imports, in
+// discovery order, ending with the project context fragment when it is
+// being saved.
+func (m Model) importedFragments() []fragment.Fragment {
+	var imported []fragment.Fragment
+	for _, f := range m.fragments {
+		if slices.Contains(m.imports, f.Path) && (f.Path != fragment.ContextFile || !m.sharingContext()) {
+			imported = append(imported, f)
+		}
+	}
+	if m.sharingContext() {
+		content := generator.ContextFragment(m.contextInput.Value())
+		imported = append(imported, fragment.Fragment{Path: fragment.ContextFile, Title: "Project Context", Content: content})
+	}
+	return imported
+}
+
+// workflowImports returns the import paths for the workflow's frontmatter.
+func (m Model) workflowImports() []string {
+	var paths []string
+	for _, f := range m.importedFragments() {
+		paths = append(paths, fragment.ImportPath(filepath.ToSlash(m.outputDir), f.Path))
+	}
+	return paths
+}
+
+// writeContextFragment saves the project context fragment when it is
+// being shared.
+func (m Model) writeContextFragment() error {
+	if !m.sharingContext() {
+		return nil
+	}
+	return writeWorkflowFile(filepath.Join(m.dir, filepath.FromSlash(fragment.ContextFile)), generator.ContextFragment(m.contextInput.Value()))
+}
+
+func (m Model) viewImports() string {
+	var b strings.Builder
+	b.WriteString("  " + m.styles.ImportIcon + " Shared fragments (imports):\n")
+	row := m.fragmentRow()
+	cursor := func(i int) string {
+		if i == row {
+			return "  " + m.styles.Cursor + " "
+		}
+		return "    "
+	}
+	for i, f := range m.fragments {
+		check := m.styles.Unchecked.Render("[ ]")
+		nameStyle := m.styles.UnselectedItem
+		if slices.Contains(m.imports, f.Path) {
+			check = m.styles.Checked.Render("[" + m.styles.CheckIcon + "]")
+			nameStyle = m.styles.SelectedItem
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s  %s\n", cursor(i), check, nameStyle.Render(f.Title), m.styles.ItemDescInline.Render(f.Path)))
+	}
+	if len(m.fragments) == 0 {
+		b.WriteString(m.styles.ItemDesc.Render("None found in " + fragment.Dir + "/"))
+		b.WriteString("\n")
+	}
+
+	check := m.styles.Unchecked.Render("[ ]")
+	nameStyle := m.styles.UnselectedItem
+	if m.shareContext {
+		check = m.styles.Checked.Render("[" + m.styles.CheckIcon + "]")
+		nameStyle = m.styles.SelectedItem
+	}
+	desc := "write " + fragment.ContextFile + " and import it instead of inlining the context"
+	if slices.ContainsFunc(m.fragments, func(f fragment.Fragment) bool { return f.Path == fragment.ContextFile }) {
+		desc = "replace " + fragment.ContextFile + " and import it instead of inlining the context"
+	}
+	if strings.TrimSpace(m.contextInput.Value()) == "" {
+		desc = "add a project context above first"
+	}
+	b.WriteString(fmt.Sprintf("%s%s %s  %s\n", cursor(len(m.fragments)), check,
+		nameStyle.Render("Save project context as a fragment"), m.styles.ItemDescInline.Render(desc)))
+	return b.String()
+}
+
+// importedPreviewLines returns the preview lines for the imported
+// fragments, each under a heading naming where it comes from.
+func (m Model) importedPreviewLines() (plain, styled []string) {
+	rule := "──"
+	if m.styles.ascii {
+		rule = "--"
+	}
+	for _, f := range m.importedFragments() {
+		heading := fmt.Sprintf("%s imported from %s %s", rule, fragment.ImportPath(filepath.ToSlash(m.outputDir), f.Path), rule)
+		plain = append(plain, "", heading)
+		styled = append(styled, "", m.styles.DetailHeading.Render(heading))
+		var lines []string
+		if m.previewRendered {
+			lines = m.styles.renderMarkdown(strings.TrimRight(promptBody(f.Content), "\n"))
+		} else {
+			lines = m.styles.highlightLines(strings.TrimRight(f.Content, "\n"))
+		}
+		for _, l := range lines {
+			plain = append(plain, ansi.Strip(l))
+			styled = append(styled, l)
+		}
+	}
+	return plain, styled
+}
@@ -0,0 +1,293 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap holds every rebindable key in the wizard. Each step matches keys
+// through it, the footers and the ? overlay are rendered from it, and the
+// user's config can swap in a preset or override single actions.
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+
+	Select      key.Binding
+	Toggle      key.Binding
+	NextField   key.Binding
+	PrevField   key.Binding
+	CycleMemory key.Binding
+	Filter      key.Binding
+	NextMatch   key.Binding
+	PrevMatch   key.Binding
+	Render      key.Binding
+	Write       key.Binding
+	Add         key.Binding
+	Edit        key.Binding
+	Reset       key.Binding
+
+	Back        key.Binding
+	Quit        key.Binding
+	Help        key.Binding
+	Undo        key.Binding
+	Redo        key.Binding
+	LivePreview key.Binding
+	DismissTips key.Binding
+}
+
+// KeyPresets lists the keymap presets a config can start from.
+var KeyPresets = []string{"default", "vim", "emacs"}
+
+// DefaultKeyMap returns the wizard's standard bindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up:       binding("up", "k"),
+		Down:     binding("down", "j"),
+		PageUp:   binding("pgup"),
+		PageDown: binding("pgdown"),
+		Top:      binding("home", "g"),
+		Bottom:   binding("end", "G"),
+
+		Select:      binding("enter"),
+		Toggle:      binding(" ", "x"),
+		NextField:   binding("tab"),
+		PrevField:   binding("shift+tab"),
+		CycleMemory: binding("tab"),
+		Filter:      binding("/"),
+		NextMatch:   binding("n"),
+		PrevMatch:   binding("N"),
+		Render:      binding("m"),
+		Write:       binding("w"),
+		Add:         binding("a"),
+		Edit:        binding("e"),
+		Reset:       binding("r"),
+
+		Back:        binding("esc"),
+		Quit:        binding("q", "ctrl+c"),
+		Help:        binding("?"),
+		Undo:        binding("ctrl+z"),
+		Redo:        binding("ctrl+y"),
+		LivePreview: binding("ctrl+p"),
+		DismissTips: binding("ctrl+x"),
+	}
+}
+
+// NewKeyMap builds the keymap for a preset, then applies overrides keyed
+// by action name (see KeyActions).
+func NewKeyMap(preset string, overrides map[string][]string) (KeyMap, error) {
+	k := DefaultKeyMap()
+	switch preset {
+	case "", "default":
+	case "vim":
+		k.PageUp.SetKeys("pgup", "ctrl+b", "ctrl+u")
+		k.PageDown.SetKeys("pgdown", "ctrl+f", "ctrl+d")
+	case "emacs":
+		k.Up.SetKeys("up", "ctrl+p")
+		k.Down.SetKeys("down", "ctrl+n")
+		k.PageUp.SetKeys("pgup", "alt+v")
+		k.PageDown.SetKeys("pgdown", "ctrl+v")
+		k.Top.SetKeys("home", "alt+<")
+		k.Bottom.SetKeys("end", "alt+>")
+		k.Back.SetKeys("esc", "ctrl+g")
+		k.LivePreview.SetKeys("ctrl+o")
+	default:
+		return k, fmt.Errorf("unknown keymap %q (want one of %s)", preset, strings.Join(KeyPresets, ", "))
+	}
+
+	actions := k.actions()
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b, ok := actions[name]
+		if !ok {
+			return k, fmt.Errorf("unknown key action %q (want one of %s)", name, strings.Join(KeyActions(), ", "))
+		}
+		keys := overrides[name]
+		if len(keys) == 0 {
+			return k, fmt.Errorf("key action %q has no keys", name)
+		}
+		b.SetKeys(keys...)
+	}
+	return k, nil
+}
+
+// actions maps the config names of the actions to their bindings.
+func (k *KeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":           &k.Up,
+		"down":         &k.Down,
+		"page_up":      &k.PageUp,
+		"page_down":    &k.PageDown,
+		"top":          &k.Top,
+		"bottom":       &k.Bottom,
+		"select":       &k.Select,
+		"toggle":       &k.Toggle,
+		"next_field":   &k.NextField,
+		"prev_field":   &k.PrevField,
+		"cycle_memory": &k.CycleMemory,
+		"filter":       &k.Filter,
+		"next_match":   &k.NextMatch,
+		"prev_match":   &k.PrevMatch,
+		"render":       &k.Render,
+		"write":        &k.Write,
+		"add":          &k.Add,
+		"edit":         &k.Edit,
+		"reset":        &k.Reset,
+		"back":         &k.Back,
+		"quit":         &k.Quit,
+		"help":         &k.Help,
+		"undo":         &k.Undo,
+		"redo":         &k.Redo,
+		"live_preview": &k.LivePreview,
+		"dismiss_tips": &k.DismissTips,
+	}
+}
+
+// KeyActions returns the action names a config can rebind.
+func KeyActions() []string {
+	var k KeyMap
+	var names []string
+	for name := range k.actions() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func binding(keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...))
+}
+
+// press reports whether msg triggers b. While a text field has focus,
+// printable keys are left for the field, so q or j type rather than quit
+// or move.
+func (m Model) press(msg tea.KeyMsg, b key.Binding) bool {
+	if m.typing() && (msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace) {
+		return false
+	}
+	return key.Matches(msg, b)
+}
+
+// typing reports whether a text field on the current step has focus.
+func (m Model) typing() bool {
+	switch m.step {
+	case stepArchetype:
+		return m.archFilter.typing
+	case stepTriggers:
+		return m.triggerFilter.typing
+	case stepContext:
+		return m.contextFocus >= 0 && m.fragmentRow() < 0
+	case stepTools:
+		return m.mcpMode == mcpForm
+	case stepPreSteps:
+		return m.preStepFocus > 0
+	case stepEngine:
+		return m.engineFocus > 0
+	case stepNetwork:
+		return m.networkOnInput()
+	case stepPreview:
+		return m.searching
+	}
+	return false
+}
+
+// keyLabel renders a key name for display. In ASCII mode the arrow keys
+// keep their names.
+func keyLabel(k string, ascii bool) string {
+	switch k {
+	case "up", "down", "left", "right":
+		if ascii {
+			return k
+		}
+	}
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case " ":
+		return "space"
+	case "pgdown":
+		return "pgdn"
+	}
+	return k
+}
+
+// helpEntry is one line of help: the bindings it covers and what they do
+// on the current step.
+type helpEntry struct {
+	keys []key.Binding
+	desc string
+	// label replaces the keys for fixed keys such as the number keys.
+	label string
+}
+
+func hk(desc string, keys ...key.Binding) helpEntry { return helpEntry{keys: keys, desc: desc} }
+
+// short renders the entry's first keys, as footers show them.
+func (e helpEntry) short(ascii bool) string {
+	if e.label != "" {
+		return e.label
+	}
+	var labels []string
+	for _, b := range e.keys {
+		if len(b.Keys()) > 0 {
+			labels = append(labels, keyLabel(b.Keys()[0], ascii))
+		}
+	}
+	// Arrows read fine run together (↑↓); anything else needs a separator.
+	sep := ""
+	for _, l := range labels {
+		if !strings.Contains("↑↓←→", l) {
+			sep = "/"
+		}
+	}
+	return strings.Join(labels, sep)
+}
+
+// full renders every key of the entry, for the help overlay.
+func (e helpEntry) full(ascii bool) string {
+	if e.label != "" {
+		return e.label
+	}
+	var groups []string
+	for _, b := range e.keys {
+		var labels []string
+		for _, k := range b.Keys() {
+			labels = append(labels, keyLabel(k, ascii))
+		}
+		groups = append(groups, strings.Join(labels, "/"))
+	}
+	return strings.Join(groups, " ")
+}
+
+// footer renders a step's key hints from its help entries, ending with
+// the key for the full list.
+func (m Model) footer(entries ...helpEntry) string {
+	parts := make([]string, 0, len(entries)+1)
+	for _, e := range append(entries, hk("help", m.keys.Help)) {
+		parts = append(parts, e.short(m.styles.ascii)+" "+e.desc)
+	}
+	return m.styles.Help.Render(strings.Join(parts, " "+m.styles.Bullet+" "))
+}
+
+// keyName renders the first key of a binding for hints in the text, such
+// as "press w again", so they follow the user's keymap.
+func (m Model) keyName(b key.Binding) string {
+	return hk("", b).short(m.styles.ascii)
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// The live preview shows the workflow as it is configured. On wide
+// terminals it is a pane beside the step; on narrow ones ctrl+p shows it
+// as an overlay instead. The lines the latest change touched are marked.
+
+const (
+	livePaneMinWidth = 50
+	liveContextLines = 3
+)
+
+// refreshLive regenerates the live preview and marks the lines that differ
+// from the previous rendering.
+func (m *Model) refreshLive() {
+	if m.appliedArch < 0 || m.step == stepArchetype || m.step == stepPreview {
+		return
+	}
+	text := generator.Generate(m.workflowConfig())
+	if text == m.liveText {
+		return
+	}
+	if m.liveText != "" {
+		m.liveChanged = changedLines(strings.Split(m.liveText, "\n"), strings.Split(text, "\n"))
+	}
+	m.liveText = text
+}
+
+// changedLines returns the indexes of lines in b that are not part of the
+// longest common subsequence of a and b.
+func changedLines(a, b []string) map[int]bool {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	changed := make(map[int]bool)
+	i, j := 0, 0
+	for j < len(b) {
+		switch {
+		case i < len(a) && a[i] == b[j]:
+			i++
+			j++
+		case i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			changed[j] = true
+			j++
+		}
+	}
+	return changed
+}
+
+// livePaneWidth returns the width available beside content, or 0 when the
+// terminal is too narrow for a split layout.
+func (m Model) livePaneWidth(content string) int {
+	w := m.width - lipgloss.Width(content) - 6
+	if w < livePaneMinWidth {
+		return 0
+	}
+	return w
+}
+
+func (m Model) hasLivePreview() bool {
+	return m.liveText != "" && m.step != stepArchetype && m.step != stepPreview
+}
+
+// renderLive renders the live preview in a box of the given size, scrolled
+// so the first changed line is in view.
+func (m Model) renderLive(width, height int) string {
+	lines := strings.Split(strings.TrimRight(m.liveText, "\n"), "\n")
+	first := -1
+	for i := range lines {
+		if m.liveChanged[i] {
+			first = i
+			break
+		}
+	}
+
+	visible := max(height-4, 5)
+	start := 0
+	if first >= visible-liveContextLines {
+		start = min(first-liveContextLines, len(lines)-visible)
+	}
+	start = max(start, 0)
+	end := min(start+visible, len(lines))
+
+	inner := width - 6
+	truncated := make([]string, len(lines))
+	for i, line := range lines {
+		truncated[i] = truncate(line, inner-2)
+	}
+	highlighted := m.styles.highlightLines(strings.Join(truncated, "\n"))
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		if m.liveChanged[i] {
+			b.WriteString(m.styles.LiveChanged.Render(m.styles.ChangedBar + " " + truncated[i]))
+		} else {
+			b.WriteString("  " + highlighted[i])
+		}
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	return m.styles.LiveBox.Width(width - 2).Render(b.String())
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if width <= 1 || len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
+
+// withLivePreview lays the step content out with the live preview: side by
+// side when there is room, otherwise as an overlay toggled with ctrl+p.
+func (m Model) withLivePreview(content string) string {
+	if !m.hasLivePreview() {
+		return content
+	}
+	height := m.height - 6
+	if w := m.livePaneWidth(content); w > 0 {
+		if m.liveToggled {
+			return content
+		}
+		return lipgloss.JoinHorizontal(lipgloss.Top, content, "  ", m.renderLive(w, height))
+	}
+	if m.liveToggled && m.width > 10 {
+		return m.styles.Title.Render("Live preview") + "\n" + m.renderLive(m.width-4, height-2) + "\n" +
+			m.styles.Help.Render(m.keyName(m.keys.LivePreview)+" back to the step")
+	}
+	return content
+}
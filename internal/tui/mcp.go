@@ -0,0 +1,340 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
+
+// MCP servers are added from the tools step: the catalog picker offers
+// common servers, and the form fills in or edits a server's settings.
+type mcpMode int
+
+const (
+	mcpList mcpMode = iota
+	mcpCatalog
+	mcpForm
+)
+
+// MCP form fields, in focus order.
+const (
+	mcpFieldName = iota
+	mcpFieldTarget
+	mcpFieldEnv
+	mcpFieldAllowed
+	mcpFieldCount
+)
+
+var mcpFieldLabels = [mcpFieldCount]string{"Name", "Command/URL", "Env", "Allowed tools"}
+
+func newMCPInputs() [mcpFieldCount]textinput.Model {
+	var inputs [mcpFieldCount]textinput.Model
+	placeholders := [mcpFieldCount]string{
+		"e.g. fetch",
+		"e.g. npx -y some-mcp-server, or https://example.com/mcp",
+		"KEY=value, comma-separated",
+		"tool names, comma-separated",
+	}
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = placeholders[i]
+		inputs[i].CharLimit = 300
+		inputs[i].Width = 50
+	}
+	return inputs
+}
+
+// mcpCatalogChoices returns the catalog servers that haven't been added yet;
+// the picker shows them followed by a "custom server" entry.
+func (m Model) mcpCatalogChoices() []data.MCPServer {
+	var choices []data.MCPServer
+	for _, s := range m.patterns.MCPCatalog {
+		if !slices.ContainsFunc(m.mcpServers, func(added data.MCPServer) bool { return added.Name == s.Name }) {
+			choices = append(choices, s)
+		}
+	}
+	return choices
+}
+
+func (m Model) updateMCP(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.mcpMode == mcpCatalog {
+		choices := m.mcpCatalogChoices()
+		switch {
+		case m.press(msg, m.keys.Up):
+			if m.mcpCatalogCursor > 0 {
+				m.mcpCatalogCursor--
+			}
+		case m.press(msg, m.keys.Down):
+			if m.mcpCatalogCursor < len(choices) {
+				m.mcpCatalogCursor++
+			}
+		case m.press(msg, m.keys.Select):
+			var s data.MCPServer
+			if m.mcpCatalogCursor < len(choices) {
+				s = choices[m.mcpCatalogCursor]
+			}
+			return m, m.openMCPForm(s, -1)
+		}
+		return m, nil
+	}
+
+	switch {
+	case m.press(msg, m.keys.NextField), m.press(msg, m.keys.Down):
+		return m, m.focusMCPField((m.mcpFocus + 1) % mcpFieldCount)
+	case m.press(msg, m.keys.PrevField), m.press(msg, m.keys.Up):
+		return m, m.focusMCPField((m.mcpFocus + mcpFieldCount - 1) % mcpFieldCount)
+	case m.press(msg, m.keys.Select):
+		s, err := m.mcpFormServer()
+		if err != nil {
+			m.mcpErr = err.Error()
+			return m, nil
+		}
+		if m.mcpEditing >= 0 {
+			m.mcpServers[m.mcpEditing] = s
+		} else {
+			m.mcpServers = append(m.mcpServers, s)
+		}
+		m.closeMCP()
+		// Keep the cursor on the "add server" row, which moved down.
+		if m.mcpEditing < 0 {
+			m.toolsCursor = m.mcpAddRow()
+		}
+		return m, nil
+	}
+	m.mcpErr = ""
+	return m, m.updateMCPField(msg)
+}
+
+func (m *Model) updateMCPField(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.mcpInputs[m.mcpFocus], cmd = m.mcpInputs[m.mcpFocus].Update(msg)
+	return cmd
+}
+
+func (m *Model) focusMCPField(i int) tea.Cmd {
+	var cmd tea.Cmd
+	for j := range m.mcpInputs {
+		if j == i {
+			cmd = m.mcpInputs[j].Focus()
+		} else {
+			m.mcpInputs[j].Blur()
+		}
+	}
+	m.mcpFocus = i
+	return cmd
+}
+
+// openMCPForm fills the form from s. editing is the index of the server
+// being edited, or -1 when adding one.
+func (m *Model) openMCPForm(s data.MCPServer, editing int) tea.Cmd {
+	target := s.URL
+	if s.Command != "" {
+		target = quoteCommand(append([]string{s.Command}, s.Args...))
+	}
+	keys := make([]string, 0, len(s.Env))
+	for k := range s.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	env := make([]string, len(keys))
+	for i, k := range keys {
+		env[i] = k + "=" + s.Env[k]
+	}
+
+	m.mcpInputs[mcpFieldName].SetValue(s.Name)
+	m.mcpInputs[mcpFieldTarget].SetValue(target)
+	m.mcpInputs[mcpFieldEnv].SetValue(strings.Join(env, ", "))
+	m.mcpInputs[mcpFieldAllowed].SetValue(strings.Join(s.Allowed, ", "))
+	m.mcpDescription = s.Description
+	m.mcpEditing = editing
+	m.mcpErr = ""
+	m.mcpMode = mcpForm
+	if s.Name == "" {
+		return m.focusMCPField(mcpFieldName)
+	}
+	return m.focusMCPField(mcpFieldAllowed)
+}
+
+// closeMCP returns from the picker or form to the tools list. It reports
+// whether there was anything to close.
+func (m *Model) closeMCP() bool {
+	if m.mcpMode == mcpList {
+		return false
+	}
+	m.focusMCPField(-1)
+	m.mcpMode = mcpList
+	m.mcpCatalogCursor = 0
+	m.mcpErr = ""
+	return true
+}
+
+// mcpFormServer parses and validates the form. A target starting with
+// http:// or https:// is a URL; anything else is a command and its
+// arguments, split like a shell would.
+func (m Model) mcpFormServer() (data.MCPServer, error) {
+	s := data.MCPServer{
+		Name:        strings.TrimSpace(m.mcpInputs[mcpFieldName].Value()),
+		Description: m.mcpDescription,
+		Allowed:     splitList(m.mcpInputs[mcpFieldAllowed].Value()),
+	}
+	target := strings.TrimSpace(m.mcpInputs[mcpFieldTarget].Value())
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		s.URL = target
+	} else {
+		fields, err := splitCommand(target)
+		if err != nil {
+			return s, err
+		}
+		if len(fields) > 0 {
+			s.Command = fields[0]
+			s.Args = fields[1:]
+		}
+	}
+	for _, kv := range splitList(m.mcpInputs[mcpFieldEnv].Value()) {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return s, fmt.Errorf("env entry %q must be KEY=value", kv)
+		}
+		if s.Env == nil {
+			s.Env = make(map[string]string)
+		}
+		s.Env[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	for i, other := range m.mcpServers {
+		if i != m.mcpEditing && other.Name == s.Name {
+			return s, fmt.Errorf("a server named %q is already configured", s.Name)
+		}
+	}
+	return s, s.Validate()
+}
+
+// splitCommand splits a command line into words the way a shell does for
+// quoting: single quotes keep everything literally, double quotes allow
+// backslash escapes, and a backslash outside quotes escapes one character.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in the command")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// quoteCommand joins words into a command line splitCommand reads back,
+// single-quoting those with spaces or special characters.
+func quoteCommand(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w != "" && !strings.ContainsFunc(w, func(r rune) bool {
+			return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@%+,", r))
+		}) {
+			quoted[i] = w
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (m Model) viewMCPCatalog() string {
+	var b strings.Builder
+	b.WriteString("  " + m.styles.DetailHeading.Render("Add an MCP server") + "\n")
+	choices := m.mcpCatalogChoices()
+	for i, s := range choices {
+		cursor := "  "
+		nameStyle := m.styles.UnselectedItem
+		if i == m.mcpCatalogCursor {
+			cursor = m.styles.Cursor + " "
+			nameStyle = m.styles.SelectedItem
+		}
+		b.WriteString(fmt.Sprintf("%s%s  %s\n", cursor, nameStyle.Width(10).Render(s.Name), m.styles.ItemDescInline.Render(s.Description)))
+	}
+	cursor, nameStyle := "  ", m.styles.UnselectedItem
+	if m.mcpCatalogCursor == len(choices) {
+		cursor, nameStyle = m.styles.Cursor+" ", m.styles.SelectedItem
+	}
+	b.WriteString(fmt.Sprintf("%s%s\n", cursor, nameStyle.Render("Custom server…")))
+	b.WriteString("\n")
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
+
+func (m Model) viewMCPForm() string {
+	var b strings.Builder
+	heading := "New MCP server"
+	if m.mcpEditing >= 0 {
+		heading = "Edit MCP server"
+	}
+	b.WriteString("  " + m.styles.DetailHeading.Render(heading) + "\n")
+	if m.mcpDescription != "" {
+		b.WriteString("  " + m.styles.ItemDescInline.Render(m.mcpDescription) + "\n")
+	}
+	b.WriteString("\n")
+	for i, input := range m.mcpInputs {
+		b.WriteString(fmt.Sprintf("  %s %s\n", m.styles.DetailLabel.Render(mcpFieldLabels[i]+":"), input.View()))
+	}
+	if m.mcpErr != "" {
+		b.WriteString("\n  " + m.styles.Error.Render(m.mcpErr) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"npx -y my-mcp", []string{"npx", "-y", "my-mcp"}},
+		{"  npx\t-y  ", []string{"npx", "-y"}},
+		{`node server.js --root 'My Documents'`, []string{"node", "server.js", "--root", "My Documents"}},
+		{`run "say \"hi\"" ''`, []string{"run", `say "hi"`, ""}},
+		{`a\ b c`, []string{"a b", "c"}},
+		{`'it'"'"'s'`, []string{"it's"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+		// Quoting the words gives a line that splits back to them.
+		if back, err := splitCommand(quoteCommand(tt.want)); err != nil || !slices.Equal(back, tt.want) {
+			t.Errorf("splitCommand(quoteCommand(%q)) = %q, %v", tt.want, back, err)
+		}
+	}
+	for _, in := range []string{`npx 'unterminated`, `npx "also`, `trailing\`} {
+		if _, err := splitCommand(in); err == nil {
+			t.Errorf("splitCommand(%q) accepted an unterminated quote", in)
+		}
+	}
+	if got := quoteCommand([]string{"npx", "-y", "my-mcp", "--dir", "My Documents", "it's"}); got != `npx -y my-mcp --dir 'My Documents' 'it'\''s'` {
+		t.Errorf("quoteCommand = %s", got)
+	}
+}
@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// cycleMemory steps the memory kind through off → cache → repo, moving
+// focus off memory fields that no longer apply and keeping it on the same
+// fragment row.
+func (m *Model) cycleMemory() {
+	row := m.fragmentRow()
+	i := slices.Index(generator.MemoryKinds, m.memoryKind)
+	m.memoryKind = generator.MemoryKinds[(i+1)%len(generator.MemoryKinds)]
+	switch {
+	case row >= 0:
+		m.focusContextField(len(m.contextFields()) + row)
+	case m.contextFocus >= len(m.contextFields()):
+		m.focusContextField(fieldTimeout)
+	}
+}
+
+// memoryRetention parses the retention field. An empty field means gh-aw's
+// default.
+func (m Model) memoryRetention() (int, error) {
+	v := strings.TrimSpace(m.retentionInput.Value())
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 || n > generator.MaxRetentionDays {
+		return 0, fmt.Errorf("retention must be between 1 and %d days", generator.MaxRetentionDays)
+	}
+	return n, nil
+}
+
+// memoryConfig reads the memory settings, ignoring fields that don't apply
+// to the selected kind.
+func (m Model) memoryConfig() (generator.MemoryConfig, error) {
+	cfg := generator.MemoryConfig{Kind: m.memoryKind}
+	if !cfg.Enabled() {
+		return cfg, nil
+	}
+	cfg.Key = strings.TrimSpace(m.memoryKeyInput.Value())
+	if m.memoryKind == generator.MemoryCache {
+		n, err := m.memoryRetention()
+		if err != nil {
+			return cfg, err
+		}
+		cfg.RetentionDays = n
+	}
+	return cfg, cfg.Validate()
+}
+
+func (m Model) viewMemory() string {
+	var b strings.Builder
+	options := []struct{ kind, label string }{
+		{generator.MemoryNone, "Off"},
+		{generator.MemoryCache, "Cache"},
+		{generator.MemoryRepo, "Repo branch"},
+	}
+	var parts []string
+	for _, o := range options {
+		if o.kind == m.memoryKind {
+			parts = append(parts, m.styles.Checked.Render(m.styles.RadioOn+" ")+m.styles.SelectedItem.Render(o.label))
+		} else {
+			parts = append(parts, m.styles.Unchecked.Render(m.styles.RadioOff+" ")+m.styles.UnselectedItem.Render(o.label))
+		}
+	}
+	b.WriteString(fmt.Sprintf("  %s Remember across runs:  %s\n", m.styles.MemoryIcon, strings.Join(parts, "   ")))
+
+	switch m.memoryKind {
+	case generator.MemoryNone:
+		b.WriteString(m.styles.ItemDesc.Render("Track trends and context between executions"))
+		b.WriteString("\n")
+		return b.String()
+	case generator.MemoryCache:
+		b.WriteString(m.styles.ItemDesc.Render("Kept in the Actions cache — cheap, but entries expire"))
+		m.memoryKeyInput.Placeholder = "default"
+	case generator.MemoryRepo:
+		b.WriteString(m.styles.ItemDesc.Render("Committed to a memory/ branch — kept indefinitely and reviewable"))
+		m.memoryKeyInput.Placeholder = "default (branch memory/<key>)"
+	}
+	b.WriteString("\n")
+
+	b.WriteString(fmt.Sprintf("  %s %s\n", m.styles.DetailLabel.Render("Memory key:"), m.memoryKeyInput.View()))
+	if m.memoryKind == generator.MemoryCache {
+		b.WriteString(fmt.Sprintf("  %s %s\n", m.styles.DetailLabel.Render("Retention:"), m.retentionInput.View()))
+	}
+	if _, err := m.memoryConfig(); err != nil {
+		b.WriteString("  " + m.styles.Error.Render(err.Error()) + "\n")
+	}
+	return b.String()
+}
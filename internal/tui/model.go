@@ -2,14 +2,25 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/draft"
+	"github.com/ashleywolf/gh-aw-create/internal/ecosystem"
+	"github.com/ashleywolf/gh-aw-create/internal/fragment"
 	"github.com/ashleywolf/gh-aw-create/internal/generator"
+	"github.com/ashleywolf/gh-aw-create/internal/lint"
+	"github.com/ashleywolf/gh-aw-create/internal/policy"
 )
 
 type step int
@@ -18,10 +29,45 @@ const (
 	stepArchetype step = iota
 	stepTriggers
 	stepContext
+	stepTools
+	stepPreSteps
+	stepEngine
+	stepNetwork
 	stepPreview
 )
 
-var stepLabels = []string{"Type", "Triggers", "Context", "Generate"}
+var stepLabels = []string{"Type", "Triggers", "Context", "Tools", "Data", "Engine", "Network", "Generate"}
+
+// Options pre-fill the wizard, e.g. from command-line flags.
+type Options struct {
+	// Dir is the repository the workflow is created for. Defaults to the
+	// working directory.
+	Dir    string
+	Engine generator.EngineConfig
+	// Autosave saves the session as a draft for Dir after every change.
+	Autosave bool
+	// Resume offers to restore a saved draft before starting.
+	Resume *draft.Draft
+	// Keys is the keymap to use; nil means DefaultKeyMap.
+	Keys *KeyMap
+	// Theme is the palette to render with; nil means DefaultTheme.
+	Theme *Theme
+
+	// Context and Memory are the project context and memory kind a new
+	// session starts with, e.g. from the team's config.
+	Context string
+	Memory  string
+	// Triggers replaces archetypes' recommended triggers, keyed by
+	// archetype ID.
+	Triggers map[string][]string
+	// OutputDir is where workflows are written. Defaults to
+	// .github/workflows.
+	OutputDir string
+	// Lint overrides the severity of lint rules.
+	Lint lint.Severities
+	// Policy is the repository's policy; nil allows everything.
+	Policy *policy.Policy
+}
 
 type Model struct {
 	patterns  *data.Patterns
@@ -29,37 +75,259 @@ type Model struct {
 	width     int
 	height    int
 	quitting  bool
+	keys      KeyMap
+	styles    Styles
+	showHelp  bool
 	generated string
 	written   bool
 	writePath string
 	writeErr  string
 
-	// Step 1: archetype
-	archCursor int
+	// Policy violations in the generated workflow, which block writing
+	violations []*policy.Violation
+
+	// Lint findings for the generated workflow; writing with findings
+	// requires confirmation
+	findings     []lint.Finding
+	confirmWrite bool
+
+	// Tips the user has dismissed, keyed by tip text
+	dismissedTips map[string]bool
+
+	// Drafts: the repository the session is saved for, the last saved
+	// state, and the draft offered for resuming
+	autosaveDrafts bool
+	repo           string
+	lastSaved      string
+	resume         *draft.Draft
+	resumeErr      string
+
+	// Step 1: archetype. appliedArch is the archetype whose defaults are
+	// applied, or -1 before one is chosen
+	archCursor     int
+	appliedArch    int
+	confirmDiscard bool
+	archFilter     listFilter
+
+	// Undo and redo snapshots of the user's choices, and the text field
+	// the last edit typed into so that typing is undone as one change
+	undoStack []State
+	redoStack []State
+	lastEdit  string
+
+	// Live preview: the last rendering, the lines the latest change
+	// touched, and whether ctrl+p flipped its default visibility
+	liveText    string
+	liveChanged map[int]bool
+	liveToggled bool
+
+	// Engine settings the wizard started with, e.g. from flags
+	engineDefault generator.EngineConfig
+
+	// Defaults from the config, and where and how workflows are written.
+	// outputDir is relative to dir, the repository root.
+	defaultContext  string
+	defaultMemory   string
+	defaultTriggers map[string][]string
+	dir             string
+	outputDir       string
+	lintSeverities  lint.Severities
+	policy          *policy.Policy
 
 	// Step 2: triggers
 	triggerSelected map[string]bool
 	triggerCursor   int
+	triggerFilter   listFilter
 
 	// Step 3: context
+	nameInput    textinput.Model
 	contextInput textinput.Model
-	useMemory    bool
+	timeoutInput textinput.Model
+	contextFocus int
+
+	// Step 3: memory
+	memoryKind     string
+	memoryKeyInput textinput.Model
+	retentionInput textinput.Model
+
+	// Step 3: shared fragments found in the repository, the paths of those
+	// imported, and whether the project context is saved as one
+	fragments    []fragment.Fragment
+	imports      []string
+	shareContext bool
 
-	// Step 4: preview
-	previewScroll int
+	// Step 4: tools & permissions
+	toolsCursor   int
+	toolsets      []string
+	permOverrides map[string]string
+
+	// Step 4: MCP servers
+	mcpServers       []data.MCPServer
+	mcpMode          mcpMode
+	mcpCatalogCursor int
+	mcpInputs        [mcpFieldCount]textinput.Model
+	mcpFocus         int
+	mcpEditing       int
+	mcpDescription   string
+	mcpErr           string
+
+	// Step 5: data
+	preStepCursor int
+	preStepFocus  int
+	preSteps      []string
+	preStepParams map[string]map[string]string
+	preStepInput  textinput.Model
+
+	// Step 6: engine
+	engineCursor  int
+	engineFocus   int
+	modelInput    textinput.Model
+	maxTurnsInput textinput.Model
+	commandInput  textinput.Model
+
+	// Step 7: network
+	networkCursor     int
+	networkEcosystems []string
+	networkDeny       bool
+	networkDomains    []string
+	domainInput       textinput.Model
+	domainErr         string
+	detectedNetwork   []string
+
+	// Ecosystems detected in the repository, used for dependency pre-steps
+	// and network presets
+	ecosystems []ecosystem.Ecosystem
+
+	// Step 8: preview
+	preview         viewport.Model
+	previewRendered bool
+	searchInput     textinput.Model
+	searching       bool
+	searchQuery     string
+	searchMatches   []int
+	searchMatch     int
 }
 
-func NewModel(p *data.Patterns) Model {
+func NewModel(p *data.Patterns, opts Options) Model {
 	ti := textinput.New()
 	ti.Placeholder = "e.g., Monorepo with packages in /packages/*, uses conventional commits..."
 	ti.CharLimit = 500
 	ti.Width = 70
 
-	return Model{
+	ni := textinput.New()
+	ni.CharLimit = 60
+	ni.Width = 40
+
+	to := textinput.New()
+	to.CharLimit = 3
+	to.Width = 5
+
+	mi := textinput.New()
+	mi.CharLimit = 80
+	mi.Width = 40
+
+	mt := textinput.New()
+	mt.CharLimit = 4
+	mt.Width = 5
+
+	ci := textinput.New()
+	ci.Placeholder = "e.g., ./scripts/run-agent.sh"
+	ci.CharLimit = 200
+	ci.Width = 50
+
+	di := textinput.New()
+	di.Placeholder = "add a domain, e.g. api.example.com"
+	di.CharLimit = 253
+	di.Width = 40
+
+	mk := textinput.New()
+	mk.CharLimit = 60
+	mk.Width = 30
+
+	rd := textinput.New()
+	rd.Placeholder = "days, default 7"
+	rd.CharLimit = 2
+	rd.Width = 16
+
+	pi := textinput.New()
+	pi.CharLimit = 200
+	pi.Width = 40
+
+	keys := DefaultKeyMap()
+	if opts.Keys != nil {
+		keys = *opts.Keys
+	}
+
+	theme := DefaultTheme()
+	if opts.Theme != nil {
+		theme = *opts.Theme
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = "."
+	}
+	ecosystems := ecosystem.Detect(os.DirFS(dir))
+	repo, err := draft.RepoPath(dir)
+	if err != nil {
+		repo = dir
+	}
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = ".github/workflows"
+	}
+	if filepath.IsAbs(outputDir) {
+		if rel, err := filepath.Rel(repo, outputDir); err == nil {
+			outputDir = rel
+		}
+	}
+	ti.SetValue(opts.Context)
+	// Fragments are optional; an unreadable directory just offers none.
+	fragments, _ := fragment.Discover(os.DirFS(dir), filepath.ToSlash(outputDir))
+
+	m := Model{
 		patterns:        p,
 		triggerSelected: make(map[string]bool),
+		nameInput:       ni,
 		contextInput:    ti,
+		timeoutInput:    to,
+		memoryKeyInput:  mk,
+		retentionInput:  rd,
+		contextFocus:    fieldContext,
+		modelInput:      mi,
+		maxTurnsInput:   mt,
+		commandInput:    ci,
+		domainInput:     di,
+		mcpInputs:       newMCPInputs(),
+		preStepInput:    pi,
+		archFilter:      newListFilter(),
+		triggerFilter:   newListFilter(),
+		keys:            keys,
+		styles:          NewStyles(theme),
+		preview:         newPreview(keys),
+		searchInput:     newSearchInput(),
+		detectedNetwork: data.NetworkPresetsFor(ecosystems),
+		ecosystems:      ecosystems,
+		permOverrides:   make(map[string]string),
+		dismissedTips:   make(map[string]bool),
+		autosaveDrafts:  opts.Autosave,
+		repo:            repo,
+		appliedArch:     -1,
+		engineDefault:   opts.Engine,
+		defaultContext:  opts.Context,
+		defaultMemory:   opts.Memory,
+		defaultTriggers: opts.Triggers,
+		memoryKind:      opts.Memory,
+		dir:             repo,
+		outputDir:       outputDir,
+		fragments:       fragments,
+		lintSeverities:  opts.Lint,
+		policy:          opts.Policy,
+		resume:          opts.Resume,
 	}
+
+	m.applyEngineConfig(opts.Engine)
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
@@ -67,24 +335,80 @@ func (m Model) Init() tea.Cmd {
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	before := m.State()
+	next, cmd := m.update(msg)
+	if m, ok := next.(Model); ok {
+		if isKey {
+			if !key.Matches(keyMsg, m.keys.Undo, m.keys.Redo) {
+				m.record(before)
+			}
+			m.refreshLive()
+			m.autosave()
+		}
+		return m, cmd
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.step == stepPreview {
+			m.syncPreview()
+		}
+		return m, nil
+
+	case tea.MouseMsg:
+		if m.step == stepPreview && m.resume == nil {
+			var cmd tea.Cmd
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+		}
 		return m, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			if m.step == stepPreview {
-				m.quitting = true
-				return m, tea.Quit
-			}
+		if m.resume != nil {
 			if msg.String() == "ctrl+c" {
 				m.quitting = true
 				return m, tea.Quit
 			}
-		case "esc":
+			return m.updateResume(msg)
+		}
+
+		// ctrl+c always quits, whatever the keymap says.
+		if msg.String() == "ctrl+c" {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		if m.showHelp {
+			m.showHelp = false
+			return m, nil
+		}
+
+		switch {
+		case m.press(msg, m.keys.Quit):
+			m.quitting = true
+			return m, tea.Quit
+		case m.press(msg, m.keys.Help):
+			m.showHelp = true
+			return m, nil
+		case m.press(msg, m.keys.DismissTips):
+			m.dismissTips()
+			return m, nil
+		case m.press(msg, m.keys.LivePreview):
+			m.liveToggled = !m.liveToggled
+			return m, nil
+		case m.press(msg, m.keys.Undo):
+			return m, m.undo()
+		case m.press(msg, m.keys.Redo):
+			return m, m.redo()
+		case m.press(msg, m.keys.Back):
+			if m.closeMCP() || m.closeSearch() || m.closeFilter() {
+				return m, nil
+			}
 			if m.step > stepArchetype {
 				m.step--
 				return m, nil
@@ -98,15 +422,36 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateTriggers(msg)
 		case stepContext:
 			return m.updateContext(msg)
+		case stepTools:
+			return m.updateTools(msg)
+		case stepPreSteps:
+			return m.updatePreSteps(msg)
+		case stepEngine:
+			return m.updateEngine(msg)
+		case stepNetwork:
+			return m.updateNetwork(msg)
 		case stepPreview:
 			return m.updatePreview(msg)
 		}
 	}
 
-	if m.step == stepContext {
-		var cmd tea.Cmd
-		m.contextInput, cmd = m.contextInput.Update(msg)
-		return m, cmd
+	switch m.step {
+	case stepContext:
+		return m, m.updateContextField(msg)
+	case stepTools:
+		if m.mcpMode == mcpForm {
+			return m, m.updateMCPField(msg)
+		}
+	case stepPreSteps:
+		return m, m.updatePreStepParam(msg)
+	case stepEngine:
+		return m, m.updateEngineField(msg)
+	case stepNetwork:
+		if m.networkOnInput() {
+			var cmd tea.Cmd
+			m.domainInput, cmd = m.domainInput.Update(msg)
+			return m, cmd
+		}
 	}
 
 	return m, nil
@@ -115,76 +460,160 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // --- Step 1: Archetype ---
 
 func (m Model) updateArchetype(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.archCursor > 0 {
-			m.archCursor--
-		}
-	case "down", "j":
-		if m.archCursor < len(m.patterns.Archetypes)-1 {
-			m.archCursor++
-		}
-	case "enter":
-		// Pre-select recommended triggers
-		arch := m.patterns.Archetypes[m.archCursor]
-		m.triggerSelected = make(map[string]bool)
-		for _, t := range arch.RecommendedTriggers {
-			m.triggerSelected[t.Type] = true
+	if m.archFilter.typing {
+		cmd := m.archFilter.update(msg, m.keys, m.archetypeFields())
+		m.archCursor = moveList(m.archVisible(), m.archCursor, 0)
+		return m, cmd
+	}
+	visible := m.archVisible()
+	if cursor, ok := m.updateList(msg, visible, m.archCursor, m.archPerPage()); ok {
+		m.archCursor = cursor
+		m.confirmDiscard = false
+		return m, nil
+	}
+	switch {
+	case m.press(msg, m.keys.Filter):
+		m.confirmDiscard = false
+		return m, m.archFilter.open()
+	case m.press(msg, m.keys.Select):
+		if !slices.Contains(visible, m.archCursor) || m.archetypeBlocked(m.patterns.Archetypes[m.archCursor]) != nil {
+			return m, nil
 		}
-		m.triggerCursor = 0
+		// Re-selecting the same archetype keeps the choices made for it;
+		// switching discards them, so ask first.
+		if m.archCursor != m.appliedArch {
+			if len(m.customizations()) > 0 && !m.confirmDiscard {
+				m.confirmDiscard = true
+				return m, nil
+			}
+			m.resetForArchetype()
+		}
+		m.confirmDiscard = false
 		m.step = stepTriggers
+		return m, nil
 	}
+	m.confirmDiscard = false
 	return m, nil
 }
 
+// resetForArchetype applies the defaults of the archetype under the cursor.
+func (m *Model) resetForArchetype() {
+	arch := m.patterns.Archetypes[m.archCursor]
+	m.appliedArch = m.archCursor
+	// Pre-select recommended triggers, unless the config picks its own
+	m.triggerSelected = make(map[string]bool)
+	ids, ok := m.defaultTriggers[arch.ID]
+	if !ok {
+		for _, t := range arch.RecommendedTriggers {
+			ids = append(ids, t.Type)
+		}
+	}
+	for _, id := range ids {
+		if t, ok := m.patterns.Trigger(id); ok && m.triggerBlocked(t) == nil {
+			m.triggerSelected[id] = true
+		}
+	}
+	m.triggerCursor = 0
+	// Toolsets and permissions are derived from the archetype
+	m.toolsets = nil
+	m.permOverrides = make(map[string]string)
+	m.networkEcosystems = m.defaultNetworkEcosystems(arch)
+	m.networkDeny = false
+	m.networkDomains = nil
+	m.networkCursor = 0
+	m.preSteps = nil
+	m.preStepParams = nil
+	m.preStepCursor = 0
+}
+
 func (m Model) viewArchetype() string {
 	var b strings.Builder
-	b.WriteString(TitleStyle.Render("What type of workflow?"))
+	b.WriteString(m.styles.Title.Render("What type of workflow?"))
 	b.WriteString("\n")
-	b.WriteString(SubtitleStyle.Render("Pick the workflow that fits your use case"))
+	b.WriteString(m.styles.Subtitle.Render("Pick the workflow that fits your use case"))
 	b.WriteString("\n\n")
 
-	for i, a := range m.patterns.Archetypes {
-		emoji := data.ArchetypeEmoji(a.ID)
+	visible := m.archVisible()
+	perPage := m.archPerPage()
+	b.WriteString(m.archFilter.view(m.styles, len(visible), len(m.patterns.Archetypes)))
+	start, end := listPage(visible, m.archCursor, perPage)
+	for pos := start; pos < end; pos++ {
+		i := visible[pos]
+		a := m.patterns.Archetypes[i]
+		emoji := m.styles.ArchetypeIcon(a.ID)
 		cursor := "  "
-		style := UnselectedItem
-		descStyle := ItemDesc
+		style := m.styles.UnselectedItem
+		descStyle := m.styles.ItemDesc
 
 		if i == m.archCursor {
-			cursor = "▸ "
-			style = SelectedItem
-			descStyle = descStyle.Foreground(lipgloss.Color("#58a6ff"))
+			cursor = m.styles.Cursor + " "
+			style = m.styles.SelectedItem
+			descStyle = m.styles.ItemDescActive
 		}
 
-		b.WriteString(fmt.Sprintf("%s%s %s\n", cursor, emoji, style.Render(a.Label)))
-		b.WriteString(descStyle.Render(a.Description))
+		desc := descStyle.Render(a.Description)
+		if err := m.archetypeBlocked(a); err != nil {
+			style = m.styles.Unchecked
+			desc = m.styles.ItemDesc.Render(m.renderBlocked(err))
+		}
+		b.WriteString(fmt.Sprintf("%s%s%s %s\n", cursor, m.styles.listNumber(pos, start), emoji, style.Render(a.Label)))
+		b.WriteString(desc)
 		b.WriteString("\n")
 	}
+	b.WriteString(m.styles.viewPageInfo(visible, m.archCursor, perPage))
 
-	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("↑↓ navigate • enter select • ctrl+c quit"))
-	return b.String()
+	list := strings.TrimRight(b.String(), "\n")
+	details := m.styles.RenderArchetypeDetails(m.patterns.Archetypes[m.archCursor])
+
+	// Show the details pane beside the list when there is room, otherwise
+	// below it.
+	var out string
+	listWidth := lipgloss.Width(list)
+	if m.width-listWidth >= detailsMinWidth+8 {
+		box := m.styles.DetailsBox.Width(min(m.width-listWidth-8, detailsMaxWidth)).Render(details)
+		out = lipgloss.JoinHorizontal(lipgloss.Top, list, box)
+	} else {
+		box := m.styles.DetailsBox.UnsetMarginLeft().MarginTop(1)
+		if m.width > 8 {
+			box = box.Width(m.width - 8)
+		}
+		out = lipgloss.JoinVertical(lipgloss.Left, list, box.Render(details))
+	}
+
+	if m.confirmDiscard {
+		out += "\n\n" + strings.TrimRight(m.renderWarning(m.styles.Warning, m.discardWarning()), "\n")
+	}
+	return out + "\n\n" + m.footer(m.stepKeys()...)
 }
 
+const (
+	detailsMinWidth = 40
+	detailsMaxWidth = 72
+)
+
 // --- Step 2: Triggers ---
 
 func (m Model) updateTriggers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.triggerCursor > 0 {
-			m.triggerCursor--
-		}
-	case "down", "j":
-		if m.triggerCursor < len(data.AllTriggers)-1 {
-			m.triggerCursor++
-		}
-	case " ", "x":
-		t := data.AllTriggers[m.triggerCursor]
-		m.triggerSelected[t] = !m.triggerSelected[t]
-	case "enter":
-		m.contextInput.Focus()
+	if m.triggerFilter.typing {
+		cmd := m.triggerFilter.update(msg, m.keys, m.triggerFields())
+		m.triggerCursor = moveList(m.triggerVisible(), m.triggerCursor, 0)
+		return m, cmd
+	}
+	visible := m.triggerVisible()
+	if cursor, ok := m.updateList(msg, visible, m.triggerCursor, m.triggerPerPage()); ok {
+		m.triggerCursor = cursor
+		return m, nil
+	}
+	switch {
+	case m.press(msg, m.keys.Filter):
+		return m, m.triggerFilter.open()
+	case m.press(msg, m.keys.Toggle):
+		if slices.Contains(visible, m.triggerCursor) {
+			m.toggleTrigger(m.patterns.Triggers[m.triggerCursor])
+		}
+	case m.press(msg, m.keys.Select):
 		m.step = stepContext
-		return m, m.contextInput.Focus()
+		return m, m.focusContextField(m.contextFocus)
 	}
 	return m, nil
 }
@@ -192,124 +621,316 @@ func (m Model) updateTriggers(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) viewTriggers() string {
 	var b strings.Builder
 	arch := m.patterns.Archetypes[m.archCursor]
-	b.WriteString(TitleStyle.Render(fmt.Sprintf("Triggers for %s", arch.Label)))
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Triggers for %s", arch.Label)))
 	b.WriteString("\n")
-	b.WriteString(SubtitleStyle.Render("Recommended triggers are pre-selected — adjust as needed"))
+	b.WriteString(m.styles.Subtitle.Render("Recommended triggers are pre-selected — adjust as needed"))
 	b.WriteString("\n\n")
+	b.WriteString(m.renderTips(data.TipTriggers))
+
+	nameWidth := 0
+	for _, t := range m.patterns.Triggers {
+		nameWidth = max(nameWidth, len(t.ID))
+	}
+
+	visible := m.triggerVisible()
+	perPage := m.triggerPerPage()
+	b.WriteString(m.triggerFilter.view(m.styles, len(visible), len(m.patterns.Triggers)))
+	start, end := listPage(visible, m.triggerCursor, perPage)
+	for pos := start; pos < end; pos++ {
+		i := visible[pos]
+		t := m.patterns.Triggers[i]
+		if t.Modifier && (pos == start || !m.patterns.Triggers[visible[pos-1]].Modifier) {
+			b.WriteString("\n  " + m.styles.ItemDescInline.Render("Modifiers") + "\n")
+		}
 
-	for i, t := range data.AllTriggers {
 		cursor := "  "
 		if i == m.triggerCursor {
-			cursor = "▸ "
+			cursor = m.styles.Cursor + " "
 		}
 
-		check := Unchecked.Render("[ ]")
-		nameStyle := UnselectedItem
-		if m.triggerSelected[t] {
-			check = Checked.Render("[✓]")
-			nameStyle = SelectedItem
+		check := m.styles.Unchecked.Render("[ ]")
+		nameStyle := m.styles.UnselectedItem
+		if m.triggerSelected[t.ID] {
+			check = m.styles.Checked.Render("[" + m.styles.CheckIcon + "]")
+			nameStyle = m.styles.SelectedItem
 		}
 
-		desc := data.TriggerDescriptions[t]
-		b.WriteString(fmt.Sprintf("%s%s %s  %s\n",
-			cursor, check, nameStyle.Render(t), ItemDesc.Render(desc)))
+		desc := m.styles.ItemDescInline.Render(t.Description)
+		if t.Risk != "" && t.Risk != data.RiskLow {
+			desc += "  " + m.styles.riskStyle(t.Risk).Render(t.Risk+" risk")
+		}
+		if err := m.triggerBlocked(t); err != nil {
+			check = m.styles.Unchecked.Render("[-]")
+			nameStyle = m.styles.Unchecked
+			desc = m.renderBlocked(err)
+		}
+		b.WriteString(fmt.Sprintf("%s%s%s %s  %s\n",
+			cursor, m.styles.listNumber(pos, start), check, nameStyle.Width(nameWidth).Render(t.ID), desc))
 	}
+	b.WriteString(m.styles.viewPageInfo(visible, m.triggerCursor, perPage))
 
 	b.WriteString("\n")
-	b.WriteString(HelpStyle.Render("↑↓ navigate • space toggle • enter next • esc back"))
+	b.WriteString(m.renderTriggerWarnings())
+	b.WriteString(m.renderComboFeedback())
+	b.WriteString(m.footer(m.stepKeys()...))
 	return b.String()
 }
 
 // --- Step 3: Context ---
 
+// Context step fields, in focus order.
+const (
+	fieldName = iota
+	fieldContext
+	fieldTimeout
+	fieldMemoryKey
+	fieldRetention
+)
+
+// contextFields returns the context step's text inputs in focus order.
+// The memory fields are only present when they apply.
+func (m *Model) contextFields() []*textinput.Model {
+	fields := []*textinput.Model{&m.nameInput, &m.contextInput, &m.timeoutInput}
+	if m.memoryKind != generator.MemoryNone {
+		fields = append(fields, &m.memoryKeyInput)
+	}
+	if m.memoryKind == generator.MemoryCache {
+		fields = append(fields, &m.retentionInput)
+	}
+	return fields
+}
+
+func (m *Model) focusContextField(i int) tea.Cmd {
+	var cmd tea.Cmd
+	for j, f := range m.contextFields() {
+		if j == i {
+			cmd = f.Focus()
+		} else {
+			f.Blur()
+		}
+	}
+	m.contextFocus = i
+	return cmd
+}
+
+func (m *Model) updateContextField(msg tea.Msg) tea.Cmd {
+	if m.fragmentRow() >= 0 {
+		return nil
+	}
+	f := m.contextFields()[m.contextFocus]
+	var cmd tea.Cmd
+	*f, cmd = f.Update(msg)
+	return cmd
+}
+
 func (m Model) updateContext(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "tab":
-		m.useMemory = !m.useMemory
+	switch {
+	case m.press(msg, m.keys.CycleMemory):
+		m.cycleMemory()
 		return m, nil
-	case "enter":
-		m.generateWorkflow()
-		m.step = stepPreview
+	case m.press(msg, m.keys.Up):
+		if m.contextFocus > 0 {
+			return m, m.focusContextField(m.contextFocus - 1)
+		}
+		return m, nil
+	case m.press(msg, m.keys.Down):
+		if m.contextFocus < m.contextRows()-1 {
+			return m, m.focusContextField(m.contextFocus + 1)
+		}
+		return m, nil
+	case m.fragmentRow() >= 0 && m.press(msg, m.keys.Toggle):
+		m.toggleFragmentRow()
+		return m, nil
+	case m.press(msg, m.keys.Select):
+		if _, err := m.workflowName(); err != nil {
+			return m, m.focusContextField(fieldName)
+		}
+		if _, err := m.timeoutOverride(); err != nil || m.timeoutBlocked() != nil {
+			return m, m.focusContextField(fieldTimeout)
+		}
+		if _, err := m.memoryRetention(); err != nil && m.memoryKind == generator.MemoryCache {
+			return m, m.focusContextField(fieldRetention)
+		}
+		if _, err := m.memoryConfig(); err != nil {
+			return m, m.focusContextField(fieldMemoryKey)
+		}
+		m.focusContextField(-1)
+		m.step = stepTools
 		return m, nil
 	}
-	var cmd tea.Cmd
-	m.contextInput, cmd = m.contextInput.Update(msg)
-	return m, cmd
+	return m, m.updateContextField(msg)
+}
+
+func (m Model) defaultWorkflowName() string {
+	return m.patterns.Archetypes[m.archCursor].WorkflowName()
+}
+
+// workflowName returns the name the workflow file is written under, falling
+// back to one derived from the archetype.
+func (m Model) workflowName() (string, error) {
+	name := strings.TrimSpace(m.nameInput.Value())
+	if name == "" {
+		return m.defaultWorkflowName(), nil
+	}
+	if err := generator.ValidateName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// timeoutOverride parses the timeout field. An empty field means no
+// override.
+func (m Model) timeoutOverride() (int, error) {
+	v := strings.TrimSpace(m.timeoutInput.Value())
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("timeout must be a positive number of minutes")
+	}
+	return n, nil
+}
+
+func (m Model) resolvedTimeout() data.Timeout {
+	override, _ := m.timeoutOverride()
+	arch := m.patterns.Archetypes[m.archCursor]
+	return m.patterns.ResolveTimeout(arch, data.TriggerEvents(m.selectedTriggers()), override)
 }
 
 func (m Model) viewContext() string {
 	var b strings.Builder
 	arch := m.patterns.Archetypes[m.archCursor]
-	b.WriteString(TitleStyle.Render(fmt.Sprintf("Context for %s", arch.Label)))
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Context for %s", arch.Label)))
 	b.WriteString("\n")
-	b.WriteString(SubtitleStyle.Render("Optional — add project details for a better workflow"))
+	b.WriteString(m.styles.Subtitle.Render("Optional — add project details for a better workflow"))
 	b.WriteString("\n\n")
+	b.WriteString(m.renderTips(data.TipContext))
 
-	memCheck := Unchecked.Render("[ ]")
-	memStyle := UnselectedItem
-	if m.useMemory {
-		memCheck = Checked.Render("[✓]")
-		memStyle = SelectedItem
+	m.nameInput.Placeholder = m.defaultWorkflowName()
+	b.WriteString("  Workflow name:\n")
+	b.WriteString("  " + m.nameInput.View())
+	b.WriteString("\n")
+	if name, err := m.workflowName(); err != nil {
+		b.WriteString("  " + m.styles.Error.Render(err.Error()) + "\n")
+	} else {
+		for _, match := range m.patterns.MatchAntiPatterns(name, "") {
+			b.WriteString(m.renderWarning(m.styles.Warning, match.String()))
+		}
 	}
-	b.WriteString(fmt.Sprintf("  %s %s\n", memCheck, memStyle.Render("🧠 Remember across runs")))
-	b.WriteString(ItemDesc.Render("Track trends and context between executions"))
-	b.WriteString("\n\n")
+	b.WriteString("\n")
 
 	b.WriteString("  Project context:\n")
 	b.WriteString("  " + m.contextInput.View())
 	b.WriteString("\n\n")
 
-	b.WriteString(HelpStyle.Render("tab toggle memory • enter generate • esc back"))
+	defaults := m.patterns.ResolveTimeout(arch, data.TriggerEvents(m.selectedTriggers()), 0)
+	m.timeoutInput.Placeholder = strconv.Itoa(defaults.Minutes)
+	b.WriteString("  Timeout (minutes):\n")
+	b.WriteString("  " + m.timeoutInput.View())
+	b.WriteString("\n")
+	if _, err := m.timeoutOverride(); err != nil {
+		b.WriteString("  " + m.styles.Error.Render(err.Error()))
+	} else {
+		t := m.resolvedTimeout()
+		explain := fmt.Sprintf("%d min — %s", t.Minutes, t.Source)
+		if t.Minutes != defaults.Minutes {
+			explain += fmt.Sprintf(" (default %d min — %s)", defaults.Minutes, defaults.Source)
+		}
+		b.WriteString(m.styles.ItemDesc.Render(explain))
+		if err := m.timeoutBlocked(); err != nil {
+			b.WriteString("\n" + m.styles.ItemDesc.Render(m.renderBlocked(err)))
+		}
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(m.viewMemory())
+	b.WriteString("\n")
+
+	b.WriteString(m.viewImports())
+	b.WriteString("\n")
+
+	b.WriteString(m.footer(m.stepKeys()...))
 	return b.String()
 }
 
-// --- Step 4: Preview ---
+// --- Step 8: Preview ---
 
-func (m *Model) generateWorkflow() {
-	arch := m.patterns.Archetypes[m.archCursor]
-	var triggers []string
-	for _, t := range data.AllTriggers {
-		if m.triggerSelected[t] {
+func (m Model) selectedTriggers() []data.TriggerSpec {
+	var triggers []data.TriggerSpec
+	for _, t := range m.patterns.Triggers {
+		if m.triggerSelected[t.ID] {
 			triggers = append(triggers, t)
 		}
 	}
+	return triggers
+}
 
+func (m Model) workflowConfig() generator.WorkflowConfig {
+	engine, _ := m.engineConfig()
+	memory, _ := m.memoryConfig()
 	cfg := generator.WorkflowConfig{
-		Archetype:      arch,
-		Triggers:       triggers,
+		Archetype:      m.patterns.Archetypes[m.archCursor],
+		Triggers:       m.selectedTriggers(),
 		ProjectContext: m.contextInput.Value(),
-		UseMemory:      m.useMemory,
+		Memory:         memory,
+		TimeoutMinutes: m.resolvedTimeout().Minutes,
+		Engine:         engine,
+		Toolsets:       m.toolsets,
+		Network:        m.networkConfig(),
+		MCPServers:     m.mcpServers,
+		PreSteps:       m.preStepConfig(),
+		Ecosystems:     m.ecosystems,
+		Imports:        m.workflowImports(),
+	}
+	if m.sharingContext() {
+		cfg.ProjectContext = ""
 	}
-	m.generated = generator.Generate(cfg)
+	if m.policy != nil && cfg.Toolsets == nil {
+		if toolsets := generator.Toolsets(cfg); len(m.policy.AllowedToolsets(toolsets)) < len(toolsets) {
+			cfg.Toolsets = m.policy.AllowedToolsets(toolsets)
+		}
+	}
+	cfg.Permissions = m.mergePermissions(cfg)
+	return cfg
 }
 
-func (m Model) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case "up", "k":
-		if m.previewScroll > 0 {
-			m.previewScroll--
+func (m *Model) generateWorkflow() {
+	m.generated = generator.Generate(m.workflowConfig())
+	m.confirmWrite = false
+	m.findings = nil
+	m.violations = nil
+	if w, err := lint.Parse(m.outputPath(), m.generated); err == nil {
+		for _, f := range m.importedFragments() {
+			w.Imported += "\n" + f.Content
 		}
-	case "down", "j":
-		m.previewScroll++
-	case "w":
-		m.writeFile()
-	case "q":
-		m.quitting = true
-		return m, tea.Quit
+		m.findings = m.lintSeverities.Apply(lint.Lint(m.patterns, w))
+		m.violations = m.policy.Check(w)
 	}
-	return m, nil
+	m.syncPreview()
+}
+
+func (m Model) outputPath() string {
+	name, _ := m.workflowName()
+	return filepath.Join(m.dir, m.outputDir, name+".md")
 }
 
 func (m *Model) writeFile() {
-	if m.written {
+	if m.written || len(m.violations) > 0 {
 		return
 	}
-	arch := m.patterns.Archetypes[m.archCursor]
-	name := strings.ReplaceAll(strings.ToLower(arch.Label), " ", "-")
-	m.writePath = fmt.Sprintf(".github/workflows/%s.md", name)
+	if len(m.findings) > 0 && !m.confirmWrite {
+		m.confirmWrite = true
+		return
+	}
+	m.writePath = m.outputPath()
 
-	// Create directory and write file
-	err := writeWorkflowFile(m.writePath, m.generated)
+	// Write the shared context first so the workflow never imports a
+	// missing fragment.
+	err := m.writeContextFragment()
+	if err == nil {
+		err = writeWorkflowFile(m.writePath, m.generated)
+	}
 	if err != nil {
 		m.writeErr = err.Error()
 		return
@@ -319,61 +940,73 @@ func (m *Model) writeFile() {
 
 func (m Model) viewPreview() string {
 	var b strings.Builder
-	arch := m.patterns.Archetypes[m.archCursor]
 
 	if m.written {
-		b.WriteString(SuccessStyle.Render(fmt.Sprintf("✓ Written to %s", m.writePath)))
+		written := m.writePath
+		if m.sharingContext() {
+			written += " and " + fragment.ContextFile
+		}
+		b.WriteString(m.styles.Success.Render(fmt.Sprintf("%s Written to %s", m.styles.CheckIcon, written)))
 		b.WriteString("\n\n")
-		b.WriteString(TitleStyle.Render("Next steps"))
+		b.WriteString(m.styles.Title.Render("Next steps"))
 		b.WriteString("\n\n")
-		steps := []struct{ num, cmd, desc string }{
-			{"1", "", "Ensure GitHub Actions is enabled on your repo"},
-			{"2", "gh extension install github/gh-aw", "Install the gh-aw extension (if not already)"},
-			{"3", "gh aw add-wizard", "Set up your AI engine secret"},
-			{"4", fmt.Sprintf("gh aw compile %s", m.writePath), "Compile the workflow"},
-			{"5", fmt.Sprintf("git add %s %s", m.writePath, strings.Replace(m.writePath, ".md", ".lock.yml", 1)), "Stage both files"},
-			{"6", "git commit -m 'Add agentic workflow' && git push", "Commit and push"},
-			{"7", fmt.Sprintf("gh aw run %s", strings.TrimSuffix(arch.Label, " ")), "Trigger your first run"},
-		}
-		for _, s := range steps {
+		for _, s := range m.nextSteps() {
 			if s.cmd != "" {
-				b.WriteString(fmt.Sprintf("  %s. %s\n", s.num, NextStepCmd.Render(s.cmd)))
-				b.WriteString(fmt.Sprintf("     %s\n", NextStepStyle.Render(s.desc)))
+				b.WriteString(fmt.Sprintf("  %s. %s\n", s.num, m.styles.NextStepCmd.Render(s.cmd)))
+				b.WriteString(fmt.Sprintf("     %s\n", m.styles.NextStep.Render(s.desc)))
 			} else {
-				b.WriteString(fmt.Sprintf("  %s. %s\n", s.num, NextStepStyle.Render(s.desc)))
+				b.WriteString(fmt.Sprintf("  %s. %s\n", s.num, m.styles.NextStep.Render(s.desc)))
 			}
 		}
 		b.WriteString("\n")
-		b.WriteString(HelpStyle.Render("q quit"))
+		b.WriteString(m.footer(hk("quit", m.keys.Quit)))
 		return b.String()
 	}
 
-	if m.writeErr != "" {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("#f85149")).Render("Error: " + m.writeErr))
-		b.WriteString("\n\n")
-	}
+	b.WriteString(m.viewPreviewHeader())
+	b.WriteString(m.viewSearch())
 
-	b.WriteString(TitleStyle.Render(fmt.Sprintf("Preview: %s workflow", arch.Label)))
-	b.WriteString("\n")
+	b.WriteString(m.styles.PreviewBox.Render(m.preview.View()))
+	b.WriteString("\n\n")
 
-	// Show scrollable preview
-	lines := strings.Split(m.generated, "\n")
-	maxLines := m.height - 10
-	if maxLines < 5 {
-		maxLines = 20
-	}
-	if m.previewScroll >= len(lines) {
-		m.previewScroll = len(lines) - 1
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
+
+// nextStep is one of the steps to take after writing the workflow.
+type nextStep struct{ num, cmd, desc string }
+
+func (m Model) nextSteps() []nextStep {
+	stage := fmt.Sprintf("git add %s %s", m.writePath, strings.Replace(m.writePath, ".md", ".lock.yml", 1))
+	stageDesc := "Stage both files"
+	if m.sharingContext() {
+		stage += " " + filepath.FromSlash(fragment.ContextFile)
+		stageDesc = "Stage the workflow and its shared context"
 	}
-	end := m.previewScroll + maxLines
-	if end > len(lines) {
-		end = len(lines)
+	return []nextStep{
+		{"1", "", "Ensure GitHub Actions is enabled on your repo"},
+		{"2", "gh extension install github/gh-aw", "Install the gh-aw extension (if not already)"},
+		{"3", "gh aw add-wizard", m.engineSecretStep()},
+		{"4", fmt.Sprintf("gh aw compile %s", m.writePath), "Compile the workflow"},
+		{"5", stage, stageDesc},
+		{"6", "git commit -m 'Add agentic workflow' && git push", "Commit and push"},
+		{"7", fmt.Sprintf("gh aw run %s", strings.TrimSuffix(filepath.Base(m.writePath), ".md")), "Trigger your first run"},
 	}
-	visible := strings.Join(lines[m.previewScroll:end], "\n")
-	b.WriteString(PreviewBox.Render(visible))
-	b.WriteString("\n\n")
+}
 
-	b.WriteString(HelpStyle.Render("↑↓ scroll • w write to .github/workflows/ • esc back • q quit"))
+// viewPreviewHeader renders everything above the workflow box.
+func (m Model) viewPreviewHeader() string {
+	var b strings.Builder
+	if m.writeErr != "" {
+		b.WriteString(m.styles.Error.UnsetBold().Render("Error: " + m.writeErr))
+		b.WriteString("\n\n")
+	}
+	arch := m.patterns.Archetypes[m.archCursor]
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Preview: %s workflow", arch.Label)))
+	b.WriteString("\n")
+	b.WriteString(m.renderChanges())
+	b.WriteString(m.renderTips(data.TipPreview))
+	b.WriteString(m.renderFindings())
 	return b.String()
 }
 
@@ -384,6 +1017,14 @@ func (m Model) View() string {
 		return ""
 	}
 
+	if m.resume != nil {
+		return m.styles.App.Render(m.viewResume())
+	}
+
+	if m.showHelp {
+		return m.styles.App.Render(m.renderProgress() + "\n\n" + m.viewHelp())
+	}
+
 	var content string
 	switch m.step {
 	case stepArchetype:
@@ -392,12 +1033,20 @@ func (m Model) View() string {
 		content = m.viewTriggers()
 	case stepContext:
 		content = m.viewContext()
+	case stepTools:
+		content = m.viewTools()
+	case stepPreSteps:
+		content = m.viewPreSteps()
+	case stepEngine:
+		content = m.viewEngine()
+	case stepNetwork:
+		content = m.viewNetwork()
 	case stepPreview:
 		content = m.viewPreview()
 	}
 
 	progress := m.renderProgress()
-	return AppStyle.Render(progress + "\n\n" + content)
+	return m.styles.App.Render(progress + "\n\n" + m.withLivePreview(content))
 }
 
 func (m Model) renderProgress() string {
@@ -407,13 +1056,36 @@ func (m Model) renderProgress() string {
 		var rendered string
 		switch {
 		case s < m.step:
-			rendered = ProgressDone.Render(fmt.Sprintf("✓ %s", label))
+			rendered = m.styles.ProgressDone.Render(fmt.Sprintf("%s %s", m.styles.CheckIcon, label))
 		case s == m.step:
-			rendered = ProgressActive.Render(fmt.Sprintf("● %s", label))
+			rendered = m.styles.ProgressActive.Render(fmt.Sprintf("%s %s", m.styles.RadioOn, label))
 		default:
-			rendered = ProgressPending.Render(fmt.Sprintf("○ %s", label))
+			rendered = m.styles.ProgressPending.Render(fmt.Sprintf("%s %s", m.styles.RadioOff, label))
 		}
 		parts = append(parts, rendered)
 	}
-	return strings.Join(parts, ProgressPending.Render("  ─  "))
+	return strings.Join(parts, m.styles.ProgressPending.Render("  "+m.styles.Separator+"  "))
+}
+
+// renderFindings lists lint findings for the generated workflow, prompting
+// for confirmation once the user has tried to write.
+func (m Model) renderFindings() string {
+	if len(m.findings) == 0 && len(m.violations) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, f := range append(policy.Findings(m.violations), m.findings...) {
+		style := m.styles.Warning
+		if f.Severity == lint.SeverityError {
+			style = m.styles.Error
+		}
+		b.WriteString(m.renderWarning(style, fmt.Sprintf("[%s] %s", f.Rule, f.Message)))
+	}
+	if len(m.violations) > 0 {
+		b.WriteString("  " + m.styles.Error.Render("Blocked by "+m.policy.Source+" — change the choices above to write") + "\n")
+	} else if m.confirmWrite {
+		b.WriteString("  " + m.styles.Warning.Render("Press "+m.keyName(m.keys.Write)+" again to write anyway") + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
 }
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// --- Step 7: Network ---
+
+// The network step lists the presets, the default-deny toggle, the custom
+// domains and finally the domain input; networkCursor indexes into that.
+func (m Model) networkDenyRow() int   { return len(data.NetworkPresets) }
+func (m Model) networkInputRow() int  { return m.networkDenyRow() + 1 + len(m.networkDomains) }
+func (m Model) networkOnInput() bool  { return m.networkCursor == m.networkInputRow() }
+func (m Model) networkDomainRow() int { return m.networkDenyRow() + 1 }
+
+func (m *Model) moveNetworkCursor(to int) tea.Cmd {
+	m.networkCursor = to
+	if m.networkOnInput() {
+		return m.domainInput.Focus()
+	}
+	m.domainInput.Blur()
+	return nil
+}
+
+func (m Model) updateNetwork(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case m.press(msg, m.keys.Up):
+		if m.networkCursor > 0 {
+			return m, m.moveNetworkCursor(m.networkCursor - 1)
+		}
+		return m, nil
+	case m.press(msg, m.keys.Down):
+		if m.networkCursor < m.networkInputRow() {
+			return m, m.moveNetworkCursor(m.networkCursor + 1)
+		}
+		return m, nil
+	case m.press(msg, m.keys.Select):
+		if m.networkOnInput() && strings.TrimSpace(m.domainInput.Value()) != "" {
+			m.addDomain()
+			return m, nil
+		}
+		m.domainInput.Blur()
+		m.step = stepPreview
+		m.generateWorkflow()
+		m.preview.GotoTop()
+		return m, nil
+	}
+
+	if m.networkOnInput() {
+		m.domainErr = ""
+		var cmd tea.Cmd
+		m.domainInput, cmd = m.domainInput.Update(msg)
+		return m, cmd
+	}
+
+	if m.press(msg, m.keys.Toggle) {
+		switch {
+		case m.networkCursor < m.networkDenyRow():
+			id := data.NetworkPresets[m.networkCursor].ID
+			if i := slices.Index(m.networkEcosystems, id); i >= 0 {
+				m.networkEcosystems = slices.Delete(m.networkEcosystems, i, i+1)
+			} else {
+				m.networkEcosystems = append(m.networkEcosystems, id)
+			}
+		case m.networkCursor == m.networkDenyRow():
+			m.networkDeny = !m.networkDeny
+		default:
+			i := m.networkCursor - m.networkDomainRow()
+			m.networkDomains = slices.Delete(m.networkDomains, i, i+1)
+		}
+	}
+	return m, nil
+}
+
+func (m *Model) addDomain() {
+	d := strings.ToLower(strings.TrimSpace(m.domainInput.Value()))
+	if err := data.ValidateDomain(d); err != nil {
+		m.domainErr = err.Error()
+		return
+	}
+	if !slices.Contains(m.networkDomains, d) {
+		m.networkDomains = append(m.networkDomains, d)
+	}
+	m.domainInput.SetValue("")
+	m.domainErr = ""
+	// Keep the cursor on the input, which moved down one row.
+	m.networkCursor = m.networkInputRow()
+}
+
+// networkConfig returns the network settings with ecosystems in catalog
+// order.
+func (m Model) networkConfig() generator.NetworkConfig {
+	var ecosystems []string
+	for _, p := range data.NetworkPresets {
+		if slices.Contains(m.networkEcosystems, p.ID) {
+			ecosystems = append(ecosystems, p.ID)
+		}
+	}
+	return generator.NetworkConfig{
+		Deny:       m.networkDeny,
+		Ecosystems: ecosystems,
+		Domains:    slices.Clone(m.networkDomains),
+	}
+}
+
+// defaultNetworkEcosystems pre-selects the detected ecosystems for
+// archetypes whose research tips call for network access.
+func (m Model) defaultNetworkEcosystems(a data.Archetype) []string {
+	if !a.NeedsNetwork() {
+		return nil
+	}
+	return slices.Clone(m.detectedNetwork)
+}
+
+func (m Model) viewNetwork() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Render("Network access"))
+	b.WriteString("\n")
+	subtitle := "Choose which domains the agent can reach"
+	if len(m.detectedNetwork) > 0 {
+		subtitle += " — detected " + strings.Join(m.detectedNetwork, ", ") + " manifests"
+	}
+	b.WriteString(m.styles.Subtitle.Render(subtitle))
+	b.WriteString("\n\n")
+	b.WriteString(m.renderTips(data.TipNetwork))
+
+	cursor := func(row int) string {
+		if row == m.networkCursor {
+			return m.styles.Cursor + " "
+		}
+		return "  "
+	}
+	checkbox := func(on bool, label string) string {
+		if on {
+			return m.styles.Checked.Render("["+m.styles.CheckIcon+"]") + " " + m.styles.SelectedItem.Render(label)
+		}
+		return m.styles.Unchecked.Render("[ ]") + " " + m.styles.UnselectedItem.Render(label)
+	}
+
+	for i, p := range data.NetworkPresets {
+		on := slices.Contains(m.networkEcosystems, p.ID)
+		desc := strings.Join(p.Domains, ", ")
+		if slices.Contains(m.detectedNetwork, p.ID) {
+			desc += " · detected"
+		}
+		b.WriteString(fmt.Sprintf("%s%s  %s\n", cursor(i),
+			checkbox(on, fmt.Sprintf("%-16s", p.Label)), m.styles.ItemDescInline.Render(desc)))
+	}
+	b.WriteString(fmt.Sprintf("%s%s  %s\n", cursor(m.networkDenyRow()),
+		checkbox(m.networkDeny, fmt.Sprintf("%-16s", "Default-deny")),
+		m.styles.ItemDescInline.Render("drop gh-aw's default infrastructure allowlist")))
+
+	b.WriteString("\n  " + m.styles.DetailHeading.Render("Custom domains") + "\n")
+	for i, d := range m.networkDomains {
+		b.WriteString(fmt.Sprintf("%s  %s %s\n", cursor(m.networkDomainRow()+i), m.styles.Bullet, d))
+	}
+	b.WriteString(cursor(m.networkInputRow()) + m.domainInput.View() + "\n")
+	if m.domainErr != "" {
+		b.WriteString("  " + m.styles.Error.Render(m.domainErr) + "\n")
+	}
+
+	b.WriteString("\n")
+	cfg := m.networkConfig()
+	switch {
+	case cfg.IsZero():
+		b.WriteString("  " + m.styles.ItemDescInline.Render("No network block — gh-aw's defaults apply") + "\n")
+	case len(cfg.AllowedDomains()) == 0:
+		b.WriteString("  " + m.styles.Warning.Render("No network access at all") + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
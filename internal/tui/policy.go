@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// Choices the repository's policy forbids are shown disabled with the rule
+// that blocked them, and the preview refuses to write a workflow that
+// still violates it.
+
+// archetypeBlocked reports why the policy rules out an archetype: the
+// unrestricted bash or safe outputs its workflows come with.
+func (m Model) archetypeBlocked(a data.Archetype) error {
+	if generator.UsesBash(a.ID) {
+		if err := m.policy.CheckBash([]string{":*"}); err != nil {
+			return err
+		}
+	}
+	for _, o := range a.RecommendedSafeOutputs {
+		if err := m.policy.CheckSafeOutput(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m Model) triggerBlocked(t data.TriggerSpec) error {
+	return m.policy.CheckTrigger(t.EventName(), t.Branches())
+}
+
+func (m Model) toolsetBlocked(ts string) error {
+	return m.policy.CheckToolset(ts)
+}
+
+// timeoutBlocked reports whether the resolved timeout is outside the
+// policy's range.
+func (m Model) timeoutBlocked() error {
+	return m.policy.CheckTimeout(m.resolvedTimeout().Minutes)
+}
+
+// renderBlocked explains why an option is disabled.
+func (m Model) renderBlocked(err error) string {
+	return m.styles.Error.UnsetBold().Render("blocked: " + err.Error())
+}
@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// --- Step 5: Data ---
+
+// The data step lists the pre-step recipes. preStepFocus is 0 for the list
+// and numbers the selected recipe's parameters from 1; the focused
+// parameter is edited in preStepInput and written back on every change.
+
+func (m Model) cursorRecipe() data.PreStepRecipe {
+	return data.PreStepRecipes[m.preStepCursor]
+}
+
+// focusPreStepParam focuses a parameter of the recipe under the cursor;
+// 0 returns focus to the list.
+func (m *Model) focusPreStepParam(i int) tea.Cmd {
+	m.preStepFocus = i
+	if i == 0 {
+		m.preStepInput.Blur()
+		return nil
+	}
+	r := m.cursorRecipe()
+	p := r.Params[i-1]
+	m.preStepInput.SetValue(m.preStepParams[r.ID][p.Key])
+	m.preStepInput.Placeholder = p.Default
+	m.preStepInput.CursorEnd()
+	return m.preStepInput.Focus()
+}
+
+func (m *Model) updatePreStepParam(msg tea.Msg) tea.Cmd {
+	if m.preStepFocus == 0 {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.preStepInput, cmd = m.preStepInput.Update(msg)
+
+	r := m.cursorRecipe()
+	values := make(map[string]string)
+	maps.Copy(values, m.preStepParams[r.ID])
+	values[r.Params[m.preStepFocus-1].Key] = m.preStepInput.Value()
+	params := make(map[string]map[string]string)
+	maps.Copy(params, m.preStepParams)
+	params[r.ID] = values
+	m.preStepParams = params
+	return cmd
+}
+
+func (m Model) updatePreSteps(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	params := 0
+	if slices.Contains(m.preStepIDs(), m.cursorRecipe().ID) {
+		params = len(m.recipeParams(m.cursorRecipe()))
+	}
+	switch {
+	case m.press(msg, m.keys.NextField):
+		return m, m.focusPreStepParam((m.preStepFocus + 1) % (params + 1))
+	case m.press(msg, m.keys.PrevField):
+		return m, m.focusPreStepParam((m.preStepFocus + params) % (params + 1))
+	case m.press(msg, m.keys.Up):
+		if m.preStepFocus > 0 {
+			return m, m.focusPreStepParam(m.preStepFocus - 1)
+		}
+		if m.preStepCursor > 0 {
+			m.preStepCursor--
+		}
+		return m, nil
+	case m.press(msg, m.keys.Down):
+		if m.preStepFocus > 0 {
+			if m.preStepFocus < params {
+				return m, m.focusPreStepParam(m.preStepFocus + 1)
+			}
+			return m, nil
+		}
+		if m.preStepCursor < len(data.PreStepRecipes)-1 {
+			m.preStepCursor++
+		}
+		return m, nil
+	case m.press(msg, m.keys.Toggle):
+		m.togglePreStep(m.cursorRecipe().ID)
+		return m, nil
+	case m.press(msg, m.keys.Select):
+		if m.preStepErr() != nil {
+			return m, nil
+		}
+		m.focusPreStepParam(0)
+		m.step = stepEngine
+		return m, nil
+	}
+	return m, m.updatePreStepParam(msg)
+}
+
+// recipeParams returns the parameters a recipe can be edited with: none
+// when it expands into per-ecosystem steps, which don't use them.
+func (m Model) recipeParams(r data.PreStepRecipe) []data.PreStepParam {
+	expanded := generator.ExpandPreStep(generator.PreStep{Recipe: r}, m.ecosystems)
+	if len(expanded) > 1 || expanded[0].Ecosystem != nil {
+		return nil
+	}
+	return r.Params
+}
+
+// togglePreStep adds or removes a recipe, keeping library order.
+func (m *Model) togglePreStep(id string) {
+	current := m.preStepIDs()
+	next := []string{}
+	for _, r := range data.PreStepRecipes {
+		on := slices.Contains(current, r.ID)
+		if r.ID == id {
+			on = !on
+		}
+		if on {
+			next = append(next, r.ID)
+		}
+	}
+	m.preSteps = next
+}
+
+// preStepIDs returns the selected recipes, or the archetype's defaults
+// before any were toggled.
+func (m Model) preStepIDs() []string {
+	if m.preSteps != nil {
+		return m.preSteps
+	}
+	var ids []string
+	for _, s := range generator.DefaultPreSteps(m.patterns.Archetypes[m.archCursor].ID) {
+		ids = append(ids, s.Recipe.ID)
+	}
+	return ids
+}
+
+// preStepConfig returns the selected pre-steps, or nil to use the
+// archetype's defaults.
+func (m Model) preStepConfig() []generator.PreStep {
+	if m.preSteps == nil && len(m.preStepParams) == 0 {
+		return nil
+	}
+	steps := []generator.PreStep{}
+	for _, id := range m.preStepIDs() {
+		if r, ok := data.FindPreStepRecipe(id); ok {
+			steps = append(steps, generator.PreStep{Recipe: r, Params: m.preStepParams[id]})
+		}
+	}
+	return steps
+}
+
+func (m Model) preStepErr() error {
+	for _, s := range m.preStepConfig() {
+		if err := s.Recipe.Validate(s.Params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m Model) viewPreSteps() string {
+	var b strings.Builder
+	arch := m.patterns.Archetypes[m.archCursor]
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Data gathering for %s", arch.Label)))
+	b.WriteString("\n")
+	subtitle := "Pre-steps fetch data before the agent starts"
+	if finding, ok := m.patterns.ResearchFindings["pre_steps_help"]; ok {
+		subtitle = finding
+	}
+	b.WriteString(m.styles.Subtitle.Render(subtitle))
+	b.WriteString("\n\n")
+	b.WriteString(m.renderTips(data.TipPreSteps))
+
+	selected := m.preStepIDs()
+	for i, r := range data.PreStepRecipes {
+		cursor := "  "
+		if i == m.preStepCursor && m.preStepFocus == 0 {
+			cursor = m.styles.Cursor + " "
+		}
+		check := m.styles.Unchecked.Render("[ ]")
+		nameStyle := m.styles.UnselectedItem
+		on := slices.Contains(selected, r.ID)
+		if on {
+			check = m.styles.Checked.Render("[" + m.styles.CheckIcon + "]")
+			nameStyle = m.styles.SelectedItem
+		}
+		expanded := generator.ExpandPreStep(generator.PreStep{Recipe: r}, m.ecosystems)
+		var outputs []string
+		for _, s := range expanded {
+			outputs = append(outputs, s.Recipe.Output)
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s  %s\n", cursor, check,
+			nameStyle.Width(22).Render(r.Label), m.styles.ItemDescInline.Render(m.styles.Arrow+" "+strings.Join(outputs, ", "))))
+		if !on {
+			continue
+		}
+		for j, p := range m.recipeParams(r) {
+			value := m.styles.ItemDescInline.Render(p.Value(m.preStepParams[r.ID]))
+			if i == m.preStepCursor && j+1 == m.preStepFocus {
+				value = m.preStepInput.View()
+			}
+			b.WriteString(fmt.Sprintf("        %s %s\n", m.styles.DetailLabel.Render(p.Label+":"), value))
+		}
+	}
+
+	b.WriteString("\n  " + m.styles.DetailHeading.Render("Files the prompt will reference") + "\n")
+	steps := generator.PreSteps(m.workflowConfig())
+	if len(steps) == 0 {
+		b.WriteString("  " + m.styles.ItemDescInline.Render("none — the agent gathers everything itself") + "\n")
+	}
+	for _, s := range steps {
+		b.WriteString(fmt.Sprintf("    %s %s %s\n", m.styles.Bullet, s.Recipe.Output, m.styles.ItemDescInline.Render("— "+s.Recipe.Description)))
+	}
+	if err := m.preStepErr(); err != nil {
+		b.WriteString("\n  " + m.styles.Error.Render(err.Error()) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The preview is a viewport over the workflow, or over the rendered
+// prompt, with a line-number gutter and incremental search. Imported
+// fragments follow the workflow with a bar in the gutter instead. Lines with a
+// match lose their syntax highlighting so the matches stand out.
+
+// previewChrome is the number of rows around the viewport besides the
+// preview header: app padding, the progress bar, the box and the help line.
+const previewChrome = 2 + 2 + 5 + 3
+
+func newPreview(keys KeyMap) viewport.Model {
+	vp := viewport.New(0, 0)
+	vp.KeyMap.Up = keys.Up
+	vp.KeyMap.Down = keys.Down
+	vp.KeyMap.PageUp = keys.PageUp
+	vp.KeyMap.PageDown = keys.PageDown
+	// The KeyMap has no half-page or sideways scrolling, and the
+	// viewport's defaults (u, d, h, l) would otherwise act behind the
+	// user's bindings.
+	vp.KeyMap.HalfPageUp.SetEnabled(false)
+	vp.KeyMap.HalfPageDown.SetEnabled(false)
+	vp.KeyMap.Left.SetEnabled(false)
+	vp.KeyMap.Right.SetEnabled(false)
+	return vp
+}
+
+func newSearchInput() textinput.Model {
+	si := textinput.New()
+	si.Prompt = "/"
+	si.CharLimit = 80
+	si.Width = 30
+	return si
+}
+
+// previewLines returns the plain and styled lines of the current preview
+// mode: the workflow's own lines, followed by those of the fragments it
+// imports.
+func (m Model) previewLines() (plain, styled []string, own int) {
+	if m.previewRendered {
+		styled = m.styles.renderMarkdown(promptBody(m.generated))
+		plain = make([]string, len(styled))
+		for i, l := range styled {
+			plain[i] = ansi.Strip(l)
+		}
+	} else {
+		plain, styled = strings.Split(m.generated, "\n"), m.styles.highlightLines(m.generated)
+	}
+	own = len(plain)
+	importedPlain, importedStyled := m.importedPreviewLines()
+	return append(plain, importedPlain...), append(styled, importedStyled...), own
+}
+
+// syncPreview fits the viewport to the terminal and refreshes its content,
+// keeping the scroll position within bounds.
+func (m *Model) syncPreview() {
+	chrome := previewChrome + strings.Count(m.viewPreviewHeader(), "\n") + 1
+	if m.searching || m.searchQuery != "" {
+		chrome++
+	}
+	m.preview.Width = max(m.width-10, 20)
+	m.preview.Height = max(m.height-chrome, 5)
+
+	plain, styled, own := m.previewLines()
+	m.searchMatches = nil
+	// Matching on the original line keeps the offsets right where
+	// lowercasing changes a character's length in bytes.
+	var query *regexp.Regexp
+	if m.searchQuery != "" {
+		query = regexp.MustCompile("(?i)" + regexp.QuoteMeta(m.searchQuery))
+	}
+	gutter := len(fmt.Sprint(own))
+	lines := make([]string, len(plain))
+	for i, line := range plain {
+		content := styled[i]
+		if query != nil && query.MatchString(line) {
+			current := len(m.searchMatches) == m.searchMatch
+			m.searchMatches = append(m.searchMatches, i)
+			content = m.styles.markMatches(line, query, current)
+		}
+		if i < own {
+			lines[i] = m.styles.LineNumber.Render(fmt.Sprintf("%*d ", gutter, i+1)) + content
+		} else {
+			lines[i] = m.styles.Imported.Render(fmt.Sprintf("%*s ", gutter, m.styles.ImportBar)) + content
+		}
+	}
+	m.searchMatch = min(m.searchMatch, max(len(m.searchMatches)-1, 0))
+	m.preview.SetContent(strings.Join(lines, "\n"))
+	m.preview.SetYOffset(m.preview.YOffset)
+}
+
+// markMatches styles each match of query in line.
+func (s Styles) markMatches(line string, query *regexp.Regexp, current bool) string {
+	style := s.SearchMatch
+	if current {
+		style = s.SearchCurrent
+	}
+	var b strings.Builder
+	last := 0
+	for _, loc := range query.FindAllStringIndex(line, -1) {
+		b.WriteString(line[last:loc[0]])
+		b.WriteString(style.Render(line[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(line[last:])
+	return b.String()
+}
+
+// jumpToMatch scrolls the current match into view, leaving it a few lines
+// below the top.
+func (m *Model) jumpToMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	line := m.searchMatches[m.searchMatch]
+	if line < m.preview.YOffset || line >= m.preview.YOffset+m.preview.Height {
+		m.preview.SetYOffset(line - liveContextLines)
+	}
+}
+
+// cycleMatch moves to the next or previous match, wrapping around.
+func (m *Model) cycleMatch(delta int) {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	m.searchMatch = (m.searchMatch + delta + len(m.searchMatches)) % len(m.searchMatches)
+	m.syncPreview()
+	m.jumpToMatch()
+}
+
+// closeSearch cancels an open search prompt, or clears the last search.
+// It reports whether there was one.
+func (m *Model) closeSearch() bool {
+	if m.step != stepPreview || (!m.searching && m.searchQuery == "") {
+		return false
+	}
+	m.searching = false
+	m.searchInput.Blur()
+	m.searchInput.SetValue("")
+	m.searchQuery = ""
+	m.syncPreview()
+	return true
+}
+
+func (m Model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.press(msg, m.keys.Select) {
+		m.searching = false
+		m.searchInput.Blur()
+		m.syncPreview()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	if q := m.searchInput.Value(); q != m.searchQuery {
+		m.searchQuery = q
+		m.searchMatch = 0
+		// Start from the first match at or below the top of the view.
+		m.syncPreview()
+		for i, line := range m.searchMatches {
+			if line >= m.preview.YOffset {
+				m.searchMatch = i
+				break
+			}
+		}
+		m.syncPreview()
+		m.jumpToMatch()
+	}
+	return m, cmd
+}
+
+func (m Model) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+	switch {
+	case m.press(msg, m.keys.Top):
+		m.preview.GotoTop()
+	case m.press(msg, m.keys.Bottom):
+		m.preview.GotoBottom()
+	case m.press(msg, m.keys.Filter):
+		m.searching = true
+		m.searchInput.SetValue(m.searchQuery)
+		m.syncPreview()
+		return m, m.searchInput.Focus()
+	case m.press(msg, m.keys.NextMatch):
+		m.cycleMatch(1)
+	case m.press(msg, m.keys.PrevMatch):
+		m.cycleMatch(-1)
+	case m.press(msg, m.keys.Render):
+		m.previewRendered = !m.previewRendered
+		m.searchMatch = 0
+		m.syncPreview()
+		m.preview.GotoTop()
+		m.jumpToMatch()
+	case m.press(msg, m.keys.Write):
+		m.writeFile()
+		m.syncPreview()
+	default:
+		var cmd tea.Cmd
+		m.preview, cmd = m.preview.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+// viewSearch renders the search prompt or the last search's match count.
+func (m Model) viewSearch() string {
+	if m.searching {
+		return "  " + m.searchInput.View() + "  " + m.viewMatchCount() + "\n"
+	}
+	if m.searchQuery != "" {
+		return "  " + m.styles.ItemDescInline.Render("/"+m.searchQuery) + "  " + m.viewMatchCount() + "\n"
+	}
+	return ""
+}
+
+func (m Model) viewMatchCount() string {
+	switch {
+	case m.searchQuery == "":
+		return ""
+	case len(m.searchMatches) == 0:
+		return m.styles.Error.Render("no matches")
+	}
+	return m.styles.ItemDescInline.Render(fmt.Sprintf("%d of %d", m.searchMatch+1, len(m.searchMatches)))
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/draft"
+)
+
+// State is the serializable part of the wizard: the user's choices,
+// without cursors, focus or rendering state. Text fields hold what was
+// typed, valid or not.
+type State struct {
+	Step      string   `json:"step"`
+	Archetype string   `json:"archetype"`
+	Triggers  []string `json:"triggers"`
+
+	Name       string `json:"name,omitempty"`
+	Context    string `json:"context,omitempty"`
+	Timeout    string `json:"timeout,omitempty"`
+	MemoryKind string `json:"memory_kind,omitempty"`
+	MemoryKey  string `json:"memory_key,omitempty"`
+	Retention  string `json:"retention,omitempty"`
+	// Imports are the shared fragments imported, by repository path.
+	Imports      []string `json:"imports,omitempty"`
+	ShareContext bool     `json:"share_context,omitempty"`
+
+	// Toolsets and PreSteps are null while the archetype's defaults apply.
+	Toolsets      []string                     `json:"toolsets"`
+	Permissions   map[string]string            `json:"permissions,omitempty"`
+	MCPServers    []data.MCPServer             `json:"mcp_servers,omitempty"`
+	PreSteps      []string                     `json:"pre_steps"`
+	PreStepParams map[string]map[string]string `json:"pre_step_params,omitempty"`
+
+	Engine   string `json:"engine"`
+	Model    string `json:"model,omitempty"`
+	MaxTurns string `json:"max_turns,omitempty"`
+	Command  string `json:"command,omitempty"`
+
+	NetworkEcosystems []string `json:"network_ecosystems,omitempty"`
+	NetworkDeny       bool     `json:"network_deny,omitempty"`
+	NetworkDomains    []string `json:"network_domains,omitempty"`
+
+	DismissedTips []string `json:"dismissed_tips,omitempty"`
+}
+
+// State captures the user's choices.
+func (m Model) State() State {
+	var triggers []string
+	for id, on := range m.triggerSelected {
+		if on {
+			triggers = append(triggers, id)
+		}
+	}
+	sort.Strings(triggers)
+	var dismissed []string
+	for tip := range m.dismissedTips {
+		dismissed = append(dismissed, tip)
+	}
+	sort.Strings(dismissed)
+	archetype := ""
+	if m.appliedArch >= 0 {
+		archetype = m.patterns.Archetypes[m.appliedArch].ID
+	}
+
+	return State{
+		Step:              stepLabels[m.step],
+		Archetype:         archetype,
+		Triggers:          triggers,
+		Name:              m.nameInput.Value(),
+		Context:           m.contextInput.Value(),
+		Timeout:           m.timeoutInput.Value(),
+		MemoryKind:        m.memoryKind,
+		MemoryKey:         m.memoryKeyInput.Value(),
+		Retention:         m.retentionInput.Value(),
+		Imports:           slices.Clone(m.imports),
+		ShareContext:      m.shareContext,
+		Toolsets:          slices.Clone(m.toolsets),
+		Permissions:       maps.Clone(m.permOverrides),
+		MCPServers:        slices.Clone(m.mcpServers),
+		PreSteps:          slices.Clone(m.preSteps),
+		PreStepParams:     maps.Clone(m.preStepParams),
+		Engine:            data.Engines[m.engineCursor].ID,
+		Model:             m.modelInput.Value(),
+		MaxTurns:          m.maxTurnsInput.Value(),
+		Command:           m.commandInput.Value(),
+		NetworkEcosystems: slices.Clone(m.networkEcosystems),
+		NetworkDeny:       m.networkDeny,
+		NetworkDomains:    slices.Clone(m.networkDomains),
+		DismissedTips:     dismissed,
+	}
+}
+
+// applyState restores the user's choices. Cursors and focus are reset.
+func (m *Model) applyState(s State) error {
+	arch := slices.IndexFunc(m.patterns.Archetypes, func(a data.Archetype) bool { return a.ID == s.Archetype })
+	if arch < 0 {
+		return fmt.Errorf("unknown archetype %q", s.Archetype)
+	}
+	m.archCursor = arch
+	m.appliedArch = arch
+
+	m.triggerSelected = make(map[string]bool)
+	for _, id := range s.Triggers {
+		m.triggerSelected[id] = true
+	}
+	m.nameInput.SetValue(s.Name)
+	m.contextInput.SetValue(s.Context)
+	m.timeoutInput.SetValue(s.Timeout)
+	m.memoryKind = s.MemoryKind
+	m.memoryKeyInput.SetValue(s.MemoryKey)
+	m.retentionInput.SetValue(s.Retention)
+	m.imports = slices.Clone(s.Imports)
+	m.shareContext = s.ShareContext
+
+	m.toolsets = slices.Clone(s.Toolsets)
+	m.permOverrides = make(map[string]string)
+	maps.Copy(m.permOverrides, s.Permissions)
+	m.mcpServers = slices.Clone(s.MCPServers)
+	m.preSteps = slices.Clone(s.PreSteps)
+	m.preStepParams = maps.Clone(s.PreStepParams)
+
+	for i, e := range data.Engines {
+		if e.ID == s.Engine {
+			m.engineCursor = i
+		}
+	}
+	m.modelInput.SetValue(s.Model)
+	m.maxTurnsInput.SetValue(s.MaxTurns)
+	m.commandInput.SetValue(s.Command)
+
+	m.networkEcosystems = slices.Clone(s.NetworkEcosystems)
+	m.networkDeny = s.NetworkDeny
+	m.networkDomains = slices.Clone(s.NetworkDomains)
+
+	m.dismissedTips = make(map[string]bool)
+	for _, tip := range s.DismissedTips {
+		m.dismissedTips[tip] = true
+	}
+
+	m.step = stepArchetype
+	if i := slices.Index(stepLabels, s.Step); i >= 0 {
+		m.step = step(i)
+	}
+	m.triggerCursor, m.toolsCursor, m.preStepCursor, m.networkCursor = 0, 0, 0, 0
+	m.focusContextField(-1)
+	m.focusEngineField(0)
+	m.focusPreStepParam(0)
+	m.closeMCP()
+	switch m.step {
+	case stepContext:
+		m.focusContextField(fieldContext)
+	case stepPreview:
+		m.generateWorkflow()
+	}
+	return nil
+}
+
+// autosave writes the session as a draft when the choices changed since
+// the last save, and removes it once the workflow is written. A failed
+// save shouldn't interrupt the wizard, so errors are ignored.
+func (m *Model) autosave() {
+	if !m.autosaveDrafts || m.resume != nil {
+		return
+	}
+	if m.written {
+		if m.lastSaved != "" {
+			draft.Delete(m.repo)
+			m.lastSaved = ""
+		}
+		return
+	}
+	if m.step == stepArchetype {
+		return
+	}
+	state, err := json.Marshal(m.State())
+	if err != nil || string(state) == m.lastSaved {
+		return
+	}
+	arch := m.patterns.Archetypes[m.archCursor]
+	d := draft.Draft{
+		Repo:    m.repo,
+		Updated: time.Now(),
+		Summary: fmt.Sprintf("%s · %s step", arch.Label, stepLabels[m.step]),
+		State:   state,
+	}
+	if draft.Save(d) == nil {
+		m.lastSaved = string(state)
+	}
+}
+
+// updateResume handles the resume prompt shown when a draft exists.
+func (m Model) updateResume(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch strings.ToLower(msg.String()) {
+	case "y", "enter":
+		var s State
+		if err := json.Unmarshal(m.resume.State, &s); err != nil || m.applyState(s) != nil {
+			m.resumeErr = "This draft can't be restored — press n to start over"
+			return m, nil
+		}
+		m.lastSaved = string(m.resume.State)
+		m.resume = nil
+		if m.step == stepContext {
+			return m, m.focusContextField(fieldContext)
+		}
+	case "n", "esc":
+		draft.Delete(m.repo)
+		m.resume = nil
+	}
+	return m, nil
+}
+
+func (m Model) viewResume() string {
+	var b strings.Builder
+	b.WriteString(m.styles.Title.Render("Resume your last session?"))
+	b.WriteString("\n")
+	b.WriteString(m.styles.Subtitle.Render(fmt.Sprintf("%s — saved %s", m.resume.Summary, m.resume.Updated.Format("2006-01-02 15:04"))))
+	b.WriteString("\n\n")
+	if m.resumeErr != "" {
+		b.WriteString("  " + m.styles.Error.Render(m.resumeErr) + "\n\n")
+	}
+	b.WriteString(m.styles.Help.Render(strings.Join([]string{"y resume", "n start over and discard the draft", "ctrl+c quit"}, " "+m.styles.Bullet+" ")))
+	return b.String()
+}
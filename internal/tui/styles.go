@@ -1,83 +1,222 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
-
-var (
-	// Colors
-	blue      = lipgloss.Color("#58a6ff")
-	green     = lipgloss.Color("#3fb950")
-	purple    = lipgloss.Color("#bc8cff")
-	dimWhite  = lipgloss.Color("#8b949e")
-	white     = lipgloss.Color("#e6edf3")
-	darkBg    = lipgloss.Color("#161b22")
-	cardBg    = lipgloss.Color("#0d1117")
-	borderDim = lipgloss.Color("#30363d")
+import (
+	"github.com/charmbracelet/lipgloss"
 
-	// App frame
-	AppStyle = lipgloss.NewStyle().Padding(1, 2)
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+)
 
-	// Title
-	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(blue).
-			MarginBottom(1)
+// Styles holds every style the wizard renders with, built from a Theme.
+type Styles struct {
+	// App frame
+	App lipgloss.Style
 
-	// Subtitle / step description
-	SubtitleStyle = lipgloss.NewStyle().
-			Foreground(dimWhite).
-			MarginBottom(1)
+	// Title and step description
+	Title    lipgloss.Style
+	Subtitle lipgloss.Style
 
 	// Progress bar
-	ProgressActive = lipgloss.NewStyle().
-			Foreground(blue).
-			Bold(true)
-
-	ProgressDone = lipgloss.NewStyle().
-			Foreground(green).
-			Bold(true)
-
-	ProgressPending = lipgloss.NewStyle().
-			Foreground(dimWhite)
+	ProgressActive  lipgloss.Style
+	ProgressDone    lipgloss.Style
+	ProgressPending lipgloss.Style
 
 	// List items
-	SelectedItem = lipgloss.NewStyle().
-			Foreground(blue).
-			Bold(true)
-
-	UnselectedItem = lipgloss.NewStyle().
-			Foreground(white)
-
-	ItemDesc = lipgloss.NewStyle().
-			Foreground(dimWhite).
-			PaddingLeft(4)
+	SelectedItem   lipgloss.Style
+	UnselectedItem lipgloss.Style
+	ItemDesc       lipgloss.Style
+	ItemDescInline lipgloss.Style
+	ItemDescActive lipgloss.Style
 
 	// Checked/unchecked
-	Checked   = lipgloss.NewStyle().Foreground(green).Bold(true)
-	Unchecked = lipgloss.NewStyle().Foreground(dimWhite)
+	Checked   lipgloss.Style
+	Unchecked lipgloss.Style
 
 	// Preview box
-	PreviewBox = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(borderDim).
-			Padding(1, 2).
-			MarginTop(1)
+	PreviewBox lipgloss.Style
+
+	// Syntax highlighting
+	YAMLKey     lipgloss.Style
+	YAMLValue   lipgloss.Style
+	YAMLString  lipgloss.Style
+	YAMLNumber  lipgloss.Style
+	YAMLExpr    lipgloss.Style
+	YAMLPunct   lipgloss.Style
+	YAMLComment lipgloss.Style
+
+	MDHeading    lipgloss.Style
+	MDListMarker lipgloss.Style
+	MDCode       lipgloss.Style
+	MDBold       lipgloss.Style
+
+	// Preview viewport
+	LineNumber    lipgloss.Style
+	SearchMatch   lipgloss.Style
+	SearchCurrent lipgloss.Style
+	// Gutter of lines imported from shared fragments
+	Imported lipgloss.Style
+
+	// Live preview pane
+	LiveBox     lipgloss.Style
+	LiveChanged lipgloss.Style
+
+	// Archetype details pane
+	DetailsBox    lipgloss.Style
+	DetailLabel   lipgloss.Style
+	DetailHeading lipgloss.Style
 
 	// Help / footer
-	HelpStyle = lipgloss.NewStyle().
-			Foreground(dimWhite).
-			MarginTop(1)
+	Help lipgloss.Style
 
 	// Success message
-	SuccessStyle = lipgloss.NewStyle().
-			Foreground(green).
-			Bold(true)
+	Success lipgloss.Style
 
-	// Next steps
-	NextStepStyle = lipgloss.NewStyle().
-			Foreground(white).
-			PaddingLeft(2)
+	// Warnings and errors
+	Warning lipgloss.Style
+	Error   lipgloss.Style
 
-	NextStepCmd = lipgloss.NewStyle().
-			Foreground(purple).
-			Bold(true)
-)
+	// Contextual tips
+	Tip lipgloss.Style
+
+	// Next steps
+	NextStep    lipgloss.Style
+	NextStepCmd lipgloss.Style
+
+	// Icons that have an ASCII fallback
+	TipIcon    string
+	WarnIcon   string
+	StarIcon   string
+	MemoryIcon string
+	ImportIcon string
+	ImportBar  string
+	// CheckIcon ticks check boxes and finished steps.
+	CheckIcon string
+	RadioOn   string
+	RadioOff  string
+	Cursor    string
+	Bullet    string
+	Arrow     string
+	UpArrow   string
+	// Separator goes between the steps of the progress bar.
+	Separator string
+	// ChangedBar marks lines the live preview just changed.
+	ChangedBar string
+
+	ascii bool
+}
+
+// asciiBorder stands in for the rounded border in ASCII mode.
+var asciiBorder = lipgloss.Border{
+	Top: "-", Bottom: "-", Left: "|", Right: "|",
+	TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+",
+}
+
+// NewStyles builds the wizard's styles from a theme.
+func NewStyles(t Theme) Styles {
+	fg := func(c lipgloss.TerminalColor) lipgloss.Style { return lipgloss.NewStyle().Foreground(c) }
+	border := lipgloss.RoundedBorder()
+	if t.ASCII {
+		border = asciiBorder
+	}
+	box := lipgloss.NewStyle().Border(border).BorderForeground(t.Border)
+
+	s := Styles{
+		App: lipgloss.NewStyle().Padding(1, 2),
+
+		Title:    fg(t.Accent).Bold(true).MarginBottom(1),
+		Subtitle: fg(t.Muted).MarginBottom(1),
+
+		ProgressActive:  fg(t.Accent).Bold(true),
+		ProgressDone:    fg(t.Success).Bold(true),
+		ProgressPending: fg(t.Muted),
+
+		SelectedItem:   fg(t.Accent).Bold(true),
+		UnselectedItem: fg(t.Text),
+		ItemDesc:       fg(t.Muted).PaddingLeft(4),
+		ItemDescInline: fg(t.Muted),
+		ItemDescActive: fg(t.Accent).PaddingLeft(4),
+
+		Checked:   fg(t.Success).Bold(true),
+		Unchecked: fg(t.Muted),
+
+		PreviewBox: box.Padding(1, 2).MarginTop(1),
+
+		YAMLKey:     fg(t.Accent),
+		YAMLValue:   fg(t.Text),
+		YAMLString:  fg(t.String),
+		YAMLNumber:  fg(t.Code),
+		YAMLExpr:    fg(t.Secondary),
+		YAMLPunct:   fg(t.Muted),
+		YAMLComment: fg(t.Muted).Italic(true),
+
+		MDHeading:    fg(t.Accent).Bold(true),
+		MDListMarker: fg(t.Secondary),
+		MDCode:       fg(t.Code),
+		MDBold:       lipgloss.NewStyle().Bold(true),
+
+		LineNumber:    fg(t.Muted),
+		SearchMatch:   fg(t.MatchText).Background(t.Code),
+		SearchCurrent: fg(t.MatchText).Background(t.Warning).Bold(true),
+		Imported:      fg(t.Secondary),
+
+		LiveBox:     box.Padding(0, 1),
+		LiveChanged: fg(t.Success).Bold(true),
+
+		DetailsBox:    box.Padding(0, 2).MarginLeft(2),
+		DetailLabel:   fg(t.Muted).Width(14),
+		DetailHeading: fg(t.Secondary).Bold(true),
+
+		Help: fg(t.Muted).MarginTop(1),
+
+		Success: fg(t.Success).Bold(true),
+
+		Warning: fg(t.Warning).Bold(true),
+		Error:   fg(t.Error).Bold(true),
+
+		Tip: fg(t.Muted).Italic(true),
+
+		NextStep:    fg(t.Text).PaddingLeft(2),
+		NextStepCmd: fg(t.Secondary).Bold(true),
+
+		TipIcon:    "💡",
+		WarnIcon:   "⚠",
+		StarIcon:   "★",
+		MemoryIcon: "🧠",
+		ImportIcon: "📎",
+		ImportBar:  "│",
+		CheckIcon:  "✓",
+		RadioOn:    "●",
+		RadioOff:   "○",
+		Cursor:     "▸",
+		Bullet:     "•",
+		Arrow:      "→",
+		UpArrow:    "↑",
+		Separator:  "─",
+		ChangedBar: "▌",
+		ascii:      t.ASCII,
+	}
+
+	// Without color, emphasis has to come from the text attributes.
+	if t.Monochrome {
+		s.SelectedItem = s.SelectedItem.Underline(true)
+		s.ItemDescActive = s.ItemDescActive.Underline(true)
+		s.MDCode = s.MDCode.Italic(true)
+		s.SearchMatch = s.SearchMatch.Reverse(true)
+		s.SearchCurrent = s.SearchCurrent.Reverse(true).Underline(true)
+		s.LiveChanged = s.LiveChanged.Reverse(true)
+	}
+	if t.ASCII {
+		s.TipIcon, s.WarnIcon, s.StarIcon, s.MemoryIcon, s.ImportIcon, s.ImportBar = "*", "!", "*", "[mem]", "[imp]", "|"
+		s.CheckIcon, s.RadioOn, s.RadioOff, s.Cursor = "x", "*", "o", ">"
+		s.Bullet, s.Arrow, s.UpArrow, s.Separator, s.ChangedBar = "-", "->", "^", "-", ">"
+	}
+	return s
+}
+
+// ArchetypeIcon returns the archetype's emoji, or its ASCII tag in ASCII
+// mode.
+func (s Styles) ArchetypeIcon(id string) string {
+	if s.ascii {
+		return data.ArchetypeASCII(id)
+	}
+	return data.ArchetypeEmoji(id)
+}
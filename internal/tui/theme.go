@@ -0,0 +1,124 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme is a named palette the styles are built from.
+type Theme struct {
+	Name string
+
+	Accent    lipgloss.TerminalColor // titles, selection, YAML keys
+	Success   lipgloss.TerminalColor // done steps, checked items
+	Secondary lipgloss.TerminalColor // headings, expressions, list markers
+	Warning   lipgloss.TerminalColor
+	Error     lipgloss.TerminalColor
+	Code      lipgloss.TerminalColor // code spans, numbers, search matches
+	String    lipgloss.TerminalColor // YAML strings
+	Muted     lipgloss.TerminalColor // descriptions, hints
+	Text      lipgloss.TerminalColor
+	Border    lipgloss.TerminalColor
+	// MatchText is drawn over Code and Warning for search matches.
+	MatchText lipgloss.TerminalColor
+
+	// Monochrome themes mark emphasis with bold, underline and reverse
+	// video instead of color.
+	Monochrome bool
+	// ASCII replaces emoji and box-drawing borders for terminals and fonts
+	// that can't show them.
+	ASCII bool
+}
+
+// ThemeNames lists the themes a config can pick, plus "auto".
+var ThemeNames = []string{"auto", "dark", "light", "high-contrast", "monochrome"}
+
+var themes = map[string]Theme{
+	"dark": {
+		Accent:    lipgloss.Color("#58a6ff"),
+		Success:   lipgloss.Color("#3fb950"),
+		Secondary: lipgloss.Color("#bc8cff"),
+		Warning:   lipgloss.Color("#d29922"),
+		Error:     lipgloss.Color("#f85149"),
+		Code:      lipgloss.Color("#ffa657"),
+		String:    lipgloss.Color("#a5d6ff"),
+		Muted:     lipgloss.Color("#8b949e"),
+		Text:      lipgloss.Color("#e6edf3"),
+		Border:    lipgloss.Color("#30363d"),
+		MatchText: lipgloss.Color("#0d1117"),
+	},
+	"light": {
+		Accent:    lipgloss.Color("#0969da"),
+		Success:   lipgloss.Color("#1a7f37"),
+		Secondary: lipgloss.Color("#8250df"),
+		Warning:   lipgloss.Color("#9a6700"),
+		Error:     lipgloss.Color("#cf222e"),
+		Code:      lipgloss.Color("#bc4c00"),
+		String:    lipgloss.Color("#0a3069"),
+		Muted:     lipgloss.Color("#59636e"),
+		Text:      lipgloss.Color("#1f2328"),
+		Border:    lipgloss.Color("#d0d7de"),
+		MatchText: lipgloss.Color("#ffffff"),
+	},
+	"high-contrast": {
+		Accent:    lipgloss.Color("#71b7ff"),
+		Success:   lipgloss.Color("#26cd4d"),
+		Secondary: lipgloss.Color("#dbb7ff"),
+		Warning:   lipgloss.Color("#f0b72f"),
+		Error:     lipgloss.Color("#ff9492"),
+		Code:      lipgloss.Color("#ffb757"),
+		String:    lipgloss.Color("#addcff"),
+		Muted:     lipgloss.Color("#d1d7e0"),
+		Text:      lipgloss.Color("#ffffff"),
+		Border:    lipgloss.Color("#9ea7b3"),
+		MatchText: lipgloss.Color("#000000"),
+	},
+	"monochrome": {
+		Accent:     lipgloss.NoColor{},
+		Success:    lipgloss.NoColor{},
+		Secondary:  lipgloss.NoColor{},
+		Warning:    lipgloss.NoColor{},
+		Error:      lipgloss.NoColor{},
+		Code:       lipgloss.NoColor{},
+		String:     lipgloss.NoColor{},
+		Muted:      lipgloss.NoColor{},
+		Text:       lipgloss.NoColor{},
+		Border:     lipgloss.NoColor{},
+		MatchText:  lipgloss.NoColor{},
+		Monochrome: true,
+	},
+}
+
+// ResolveTheme returns the named theme. "auto" (or "") picks dark or light
+// from the terminal background, and NO_COLOR forces monochrome whatever
+// the name.
+func ResolveTheme(name string, ascii bool) (Theme, error) {
+	if name == "" {
+		name = "auto"
+	}
+	if _, ok := themes[name]; !ok && name != "auto" {
+		return Theme{}, fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(ThemeNames, ", "))
+	}
+	switch {
+	case os.Getenv("NO_COLOR") != "":
+		name = "monochrome"
+	case name == "auto" && lipgloss.HasDarkBackground():
+		name = "dark"
+	case name == "auto":
+		name = "light"
+	}
+	t := themes[name]
+	t.Name = name
+	t.ASCII = ascii
+	return t, nil
+}
+
+// DefaultTheme is the dark theme the wizard was designed with.
+func DefaultTheme() Theme {
+	t := themes["dark"]
+	t.Name = "dark"
+	return t
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+)
+
+// --- Step 4: Tools & permissions ---
+
+// The tools step lists the github toolsets, the permission scopes, the MCP
+// servers and finally the "add server" row; toolsCursor indexes into that
+// combined list.
+func (m Model) mcpRow() int    { return len(generator.GitHubToolsets) + len(generator.PermissionScopes) }
+func (m Model) mcpAddRow() int { return m.mcpRow() + len(m.mcpServers) }
+func (m Model) toolsRows() int { return m.mcpAddRow() + 1 }
+
+func (m Model) updateTools(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.mcpMode != mcpList {
+		return m.updateMCP(msg)
+	}
+	switch {
+	case m.press(msg, m.keys.Up):
+		if m.toolsCursor > 0 {
+			m.toolsCursor--
+		}
+	case m.press(msg, m.keys.Down):
+		if m.toolsCursor < m.toolsRows()-1 {
+			m.toolsCursor++
+		}
+	case m.press(msg, m.keys.Toggle):
+		switch {
+		case m.toolsCursor < len(generator.GitHubToolsets):
+			m.toggleToolset(generator.GitHubToolsets[m.toolsCursor])
+		case m.toolsCursor < m.mcpRow():
+			m.cyclePermission(generator.PermissionScopes[m.toolsCursor-len(generator.GitHubToolsets)])
+		case m.toolsCursor < m.mcpAddRow():
+			i := m.toolsCursor - m.mcpRow()
+			m.mcpServers = slices.Delete(slices.Clone(m.mcpServers), i, i+1)
+		}
+	case m.press(msg, m.keys.Reset):
+		m.permOverrides = make(map[string]string)
+	case m.press(msg, m.keys.Add):
+		m.mcpMode = mcpCatalog
+	case m.press(msg, m.keys.Edit):
+		if m.toolsCursor >= m.mcpRow() && m.toolsCursor < m.mcpAddRow() {
+			i := m.toolsCursor - m.mcpRow()
+			return m, m.openMCPForm(m.mcpServers[i], i)
+		}
+	case m.press(msg, m.keys.Select):
+		if m.toolsCursor == m.mcpAddRow() {
+			m.mcpMode = mcpCatalog
+			return m, nil
+		}
+		m.step = stepPreSteps
+	}
+	return m, nil
+}
+
+func (m *Model) toggleToolset(ts string) {
+	if m.toolsetBlocked(ts) != nil {
+		return
+	}
+	current := generator.Toolsets(m.workflowConfig())
+	next := []string{}
+	for _, t := range generator.GitHubToolsets {
+		on := slices.Contains(current, t)
+		if t == ts {
+			on = !on
+		}
+		if on {
+			next = append(next, t)
+		}
+	}
+	m.toolsets = next
+}
+
+// cyclePermission steps a scope through none → read → write.
+func (m *Model) cyclePermission(scope string) {
+	levels := []string{generator.PermissionNone, generator.PermissionRead, generator.PermissionWrite}
+	current := m.permissions()[scope]
+	if current == "" {
+		current = generator.PermissionNone
+	}
+	next := levels[(slices.Index(levels, current)+1)%len(levels)]
+	m.permOverrides[scope] = next
+}
+
+func (m Model) permissions() map[string]string {
+	return generator.Permissions(m.workflowConfig())
+}
+
+// mergePermissions applies the user's overrides over the permissions
+// inferred for cfg. It returns nil when there are no overrides.
+func (m Model) mergePermissions(cfg generator.WorkflowConfig) map[string]string {
+	if len(m.permOverrides) == 0 {
+		return nil
+	}
+	perms := generator.InferPermissions(cfg)
+	for scope, level := range m.permOverrides {
+		perms[scope] = level
+	}
+	return perms
+}
+
+func (m Model) viewTools() string {
+	var b strings.Builder
+	arch := m.patterns.Archetypes[m.archCursor]
+	b.WriteString(m.styles.Title.Render(fmt.Sprintf("Tools & permissions for %s", arch.Label)))
+	b.WriteString("\n")
+	b.WriteString(m.styles.Subtitle.Render("Permissions are derived from the tools — trim toolsets to tighten them"))
+	b.WriteString("\n\n")
+
+	switch m.mcpMode {
+	case mcpCatalog:
+		return b.String() + m.viewMCPCatalog()
+	case mcpForm:
+		return b.String() + m.viewMCPForm()
+	}
+
+	cfg := m.workflowConfig()
+	b.WriteString(fmt.Sprintf("  %s %s\n\n", m.styles.DetailLabel.Render("Tools"), strings.Join(generator.Tools(cfg), " · ")))
+
+	row := 0
+	cursor := func() string {
+		defer func() { row++ }()
+		if row == m.toolsCursor {
+			return m.styles.Cursor + " "
+		}
+		return "  "
+	}
+
+	b.WriteString("  " + m.styles.DetailHeading.Render("GitHub toolsets") + "\n")
+	toolsets := generator.Toolsets(cfg)
+	for _, ts := range generator.GitHubToolsets {
+		check := m.styles.Unchecked.Render("[ ]")
+		nameStyle := m.styles.UnselectedItem
+		if slices.Contains(toolsets, ts) {
+			check = m.styles.Checked.Render("[" + m.styles.CheckIcon + "]")
+			nameStyle = m.styles.SelectedItem
+		}
+		blocked := ""
+		if err := m.toolsetBlocked(ts); err != nil {
+			check = m.styles.Unchecked.Render("[-]")
+			nameStyle = m.styles.Unchecked
+			blocked = "  " + m.renderBlocked(err)
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s%s\n", cursor(), check, nameStyle.Render(ts), blocked))
+	}
+
+	b.WriteString("\n  " + m.styles.DetailHeading.Render("Permissions") + "\n")
+	perms := m.permissions()
+	inferred := generator.InferPermissions(cfg)
+	var writes []string
+	for _, scope := range generator.PermissionScopes {
+		level := perms[scope]
+		if level == "" {
+			level = generator.PermissionNone
+		}
+		style := m.styles.Unchecked
+		switch level {
+		case generator.PermissionRead:
+			style = m.styles.Checked
+		case generator.PermissionWrite:
+			style = m.styles.Error
+			writes = append(writes, scope)
+		}
+		note := ""
+		if _, overridden := m.permOverrides[scope]; overridden && level != inferredLevel(inferred, scope) {
+			note = m.styles.ItemDescInline.Render(fmt.Sprintf("  (inferred: %s)", inferredLevel(inferred, scope)))
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s%s\n", cursor(), style.Width(7).Render(level), scope, note))
+	}
+
+	b.WriteString("\n  " + m.styles.DetailHeading.Render("MCP servers") + "\n")
+	for _, s := range m.mcpServers {
+		target := s.URL
+		if target == "" {
+			target = strings.Join(append([]string{s.Command}, s.Args...), " ")
+		}
+		b.WriteString(fmt.Sprintf("%s%s  %s\n", cursor(), m.styles.SelectedItem.Render(s.Name),
+			m.styles.ItemDescInline.Render(fmt.Sprintf("%s · allows %s", target, strings.Join(s.Allowed, ", ")))))
+	}
+	b.WriteString(cursor() + m.styles.UnselectedItem.Render("+ Add MCP server") + "\n")
+
+	if len(writes) > 0 {
+		b.WriteString("\n")
+		b.WriteString(m.renderWarning(m.styles.Warning, fmt.Sprintf(
+			"write access to %s — prefer safe-outputs, which apply writes in a separate job after the agent finishes",
+			strings.Join(writes, ", "))))
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.footer(m.stepKeys()...))
+	return b.String()
+}
+
+func inferredLevel(inferred map[string]string, scope string) string {
+	if level, ok := inferred[scope]; ok {
+		return level
+	}
+	return generator.PermissionNone
+}
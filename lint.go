@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/lint"
+	"github.com/ashleywolf/gh-aw-create/internal/policy"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [files...]",
+	Short: "Check workflow files against research-backed rules",
+	Long:  "Lint agentic workflow .md files. With no arguments, checks every .md file in the output directory (.github/workflows/ at the repository root unless configured). Rule severities can be changed with the lint setting, and violations of the repository's policy are always errors.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		patterns, err := data.LoadPatterns()
+		if err != nil {
+			return fmt.Errorf("loading patterns: %w", err)
+		}
+
+		cfg, err := loadConfig(config.Config{})
+		if err != nil {
+			return err
+		}
+		severities, err := lintSeverities(cfg)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		pol, err := loadPolicy()
+		if err != nil {
+			return err
+		}
+
+		files := args
+		if len(files) == 0 {
+			root, err := repoRoot()
+			if err != nil {
+				return err
+			}
+			dir := cfg.OutputDir
+			if dir == "" {
+				dir = config.DefaultOutputDir
+			}
+			dir = inRepo(root, dir)
+			files, err = filepath.Glob(filepath.Join(dir, "*.md"))
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				return fmt.Errorf("no workflow files found in %s/", dir)
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		problems, failed := 0, false
+		for _, f := range files {
+			w, err := lint.ParseFile(f)
+			if err != nil {
+				return err
+			}
+			// Imports are read first so the policy sees their sections.
+			imports := w.LoadImports()
+			findings := policy.Findings(pol.Check(w))
+			findings = append(findings, severities.Apply(append(imports, lint.Lint(patterns, w)...))...)
+			for _, finding := range findings {
+				fmt.Fprintf(out, "%s: %s\n", f, finding)
+			}
+			problems += len(findings)
+			failed = failed || lint.HasErrors(findings)
+		}
+
+		if failed {
+			return fmt.Errorf("%d problem(s) found", problems)
+		}
+		if problems == 0 {
+			fmt.Fprintf(out, "✓ %d workflow(s) checked, no problems\n", len(files))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
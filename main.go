@@ -7,7 +7,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"github.com/ashleywolf/gh-aw-create/internal/config"
 	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/draft"
 	"github.com/ashleywolf/gh-aw-create/internal/tui"
 )
 
@@ -15,14 +17,47 @@ var rootCmd = &cobra.Command{
 	Use:   "gh-aw-create",
 	Short: "Create GitHub Agentic Workflows from the terminal",
 	Long:  "Interactive TUI wizard for generating production-ready .md workflow files for GitHub Agentic Workflows (gh-aw).",
+	// main prints the error; usage is only useful for flag mistakes.
+	SilenceUsage:  true,
+	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		patterns, err := data.LoadPatterns()
 		if err != nil {
 			return fmt.Errorf("loading patterns: %w", err)
 		}
 
-		m := tui.NewModel(patterns)
-		p := tea.NewProgram(m, tea.WithAltScreen())
+		cfg, err := loadConfig(config.Config{
+			Engine:    engineFlag,
+			Model:     modelFlag,
+			MaxTurns:  maxTurnsFlag,
+			Command:   commandFlag,
+			OutputDir: outputDirFlag,
+		})
+		if err != nil {
+			return err
+		}
+		opts, err := wizardOptions(patterns, cfg)
+		if err != nil {
+			return err
+		}
+
+		if accessibleFlag || !isTerminal(os.Stdout) || os.Getenv("TERM") == "dumb" {
+			return tui.RunAccessible(patterns, opts, os.Stdin, os.Stdout)
+		}
+
+		theme, keys, err := loadUIConfig(cfg)
+		if err != nil {
+			return err
+		}
+		opts.Autosave, opts.Keys, opts.Theme = true, &keys, &theme
+		if repo, err := draft.RepoPath(opts.Dir); err == nil {
+			if d, ok, err := draft.Load(repo); err == nil && ok {
+				opts.Resume = &d
+			}
+		}
+
+		m := tui.NewModel(patterns, opts)
+		p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
 		if _, err := p.Run(); err != nil {
 			return err
 		}
@@ -30,6 +65,30 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// isTerminal reports whether f is a terminal rather than a pipe or file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+var (
+	engineFlag     string
+	modelFlag      string
+	maxTurnsFlag   int
+	commandFlag    string
+	outputDirFlag  string
+	accessibleFlag bool
+)
+
+func init() {
+	rootCmd.Flags().StringVar(&engineFlag, "engine", "", "AI engine to pre-select (copilot, claude, codex, custom)")
+	rootCmd.Flags().StringVar(&modelFlag, "model", "", "model for the engine")
+	rootCmd.Flags().IntVar(&maxTurnsFlag, "max-turns", 0, "maximum chat iterations per run (claude only)")
+	rootCmd.Flags().StringVar(&commandFlag, "command", "", "command that runs the agent (custom engine only)")
+	rootCmd.Flags().StringVar(&outputDirFlag, "output-dir", "", "directory workflows are written to (default .github/workflows)")
+	rootCmd.Flags().BoolVar(&accessibleFlag, "accessible", false, "ask plain line-by-line questions instead of the full-screen wizard (automatic when stdout isn't a terminal or TERM=dumb)")
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
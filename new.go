@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+	"github.com/ashleywolf/gh-aw-create/internal/data"
+	"github.com/ashleywolf/gh-aw-create/internal/ecosystem"
+	"github.com/ashleywolf/gh-aw-create/internal/generator"
+	"github.com/ashleywolf/gh-aw-create/internal/lint"
+	"github.com/ashleywolf/gh-aw-create/internal/policy"
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new <archetype>",
+	Short: "Generate a workflow without the wizard",
+	Long: `Generate a workflow from an archetype's defaults, the config and flags, without prompting.
+
+Choices the repository's policy (` + policy.File + `) forbids are errors, and nothing is written.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		patterns, err := data.LoadPatterns()
+		if err != nil {
+			return fmt.Errorf("loading patterns: %w", err)
+		}
+		arch, err := patterns.LookupArchetype(args[0])
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig(config.Config{
+			Engine:    engineFlag,
+			Model:     modelFlag,
+			MaxTurns:  maxTurnsFlag,
+			Command:   commandFlag,
+			OutputDir: outputDirFlag,
+			Context:   newContext,
+			Memory:    newMemory,
+		})
+		if err != nil {
+			return err
+		}
+		opts, err := wizardOptions(patterns, cfg)
+		if err != nil {
+			return err
+		}
+
+		if newTimeout < 0 {
+			return fmt.Errorf("--timeout must be a positive number of minutes")
+		}
+
+		name := newName
+		if name == "" {
+			name = arch.WorkflowName()
+		}
+		if err := generator.ValidateName(name); err != nil {
+			return fmt.Errorf("--name %q: %w", name, err)
+		}
+
+		triggers, err := newTriggers(patterns, arch, opts.Triggers, opts.Policy)
+		if err != nil {
+			return err
+		}
+
+		ecosystems := ecosystem.Detect(os.DirFS(opts.Dir))
+		wf := generator.WorkflowConfig{
+			Archetype:      arch,
+			Triggers:       triggers,
+			ProjectContext: opts.Context,
+			Memory:         generator.MemoryConfig{Kind: opts.Memory},
+			TimeoutMinutes: patterns.ResolveTimeout(arch, data.TriggerEvents(triggers), newTimeout).Minutes,
+			Engine:         opts.Engine,
+			Ecosystems:     ecosystems,
+		}
+		if cmd.Flags().Changed("toolset") {
+			for _, ts := range newToolsets {
+				if !slices.Contains(generator.GitHubToolsets, ts) {
+					return fmt.Errorf("unknown toolset %q (available: %s)", ts, strings.Join(generator.GitHubToolsets, ", "))
+				}
+			}
+			wf.Toolsets = newToolsets
+		} else if opts.Policy != nil {
+			// Like the wizard, drop inferred toolsets the policy forbids.
+			wf.Toolsets = opts.Policy.AllowedToolsets(generator.Toolsets(wf))
+		}
+		if arch.NeedsNetwork() {
+			wf.Network.Ecosystems = data.NetworkPresetsFor(ecosystems)
+		}
+
+		outputDir := opts.OutputDir
+		if outputDir == "" {
+			outputDir = config.DefaultOutputDir
+		}
+		outputDir = inRepo(opts.Dir, outputDir)
+		path := filepath.Join(outputDir, name+".md")
+		content := generator.Generate(wf)
+		w, err := lint.Parse(path, content)
+		if err != nil {
+			return err
+		}
+
+		if violations := opts.Policy.Check(w); len(violations) > 0 {
+			var errs []error
+			for _, v := range violations {
+				errs = append(errs, v)
+			}
+			return fmt.Errorf("not written, the workflow violates the policy:\n%w", errors.Join(errs...))
+		}
+		for _, f := range opts.Lint.Apply(lint.Lint(patterns, w)) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s\n", path, f)
+		}
+
+		if newStdout {
+			fmt.Fprint(cmd.OutOrStdout(), content)
+			return nil
+		}
+		if _, err := os.Stat(path); err == nil && !newForce {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+		if err := os.MkdirAll(outputDir, 0o755); err != nil {
+			return fmt.Errorf("creating directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ Written to %s\n", path)
+		return nil
+	},
+}
+
+// newTriggers resolves --trigger, falling back to the config's or the
+// archetype's triggers minus those the policy forbids.
+func newTriggers(p *data.Patterns, arch data.Archetype, configured map[string][]string, pol *policy.Policy) ([]data.TriggerSpec, error) {
+	ids, explicit := newTriggerIDs, len(newTriggerIDs) > 0
+	if !explicit {
+		var ok bool
+		if ids, ok = configured[arch.ID]; !ok {
+			for _, t := range arch.RecommendedTriggers {
+				ids = append(ids, t.Type)
+			}
+		}
+	}
+	var triggers []data.TriggerSpec
+	for _, id := range ids {
+		t, ok := p.Trigger(id)
+		if !ok {
+			return nil, fmt.Errorf("unknown trigger %q", id)
+		}
+		if err := pol.CheckTrigger(t.EventName(), t.Branches()); err != nil {
+			if explicit {
+				return nil, err
+			}
+			continue
+		}
+		triggers = append(triggers, t)
+	}
+	if len(triggers) == 0 {
+		return nil, fmt.Errorf("no triggers left for %s; pick some with --trigger", arch.ID)
+	}
+	return triggers, nil
+}
+
+var (
+	newName       string
+	newTriggerIDs []string
+	newToolsets   []string
+	newContext    string
+	newMemory     string
+	newTimeout    int
+	newStdout     bool
+	newForce      bool
+)
+
+func init() {
+	f := newCmd.Flags()
+	f.StringVar(&newName, "name", "", "workflow file name (default derived from the archetype)")
+	f.StringSliceVar(&newTriggerIDs, "trigger", nil, "trigger IDs, replacing the archetype's recommended ones")
+	f.StringSliceVar(&newToolsets, "toolset", nil, "GitHub toolsets, replacing the inferred ones")
+	f.StringVar(&newContext, "context", "", "project context for the prompt")
+	f.StringVar(&newMemory, "memory", "", "memory kind: none, cache or repo")
+	f.IntVar(&newTimeout, "timeout", 0, "timeout in minutes (default from the triggers and archetype)")
+	f.StringVar(&engineFlag, "engine", "", "AI engine (copilot, claude, codex, custom)")
+	f.StringVar(&modelFlag, "model", "", "model for the engine")
+	f.IntVar(&maxTurnsFlag, "max-turns", 0, "maximum chat iterations per run (claude only)")
+	f.StringVar(&commandFlag, "command", "", "command that runs the agent (custom engine only)")
+	f.StringVar(&outputDirFlag, "output-dir", "", "directory to write to (default .github/workflows)")
+	f.BoolVar(&newStdout, "stdout", false, "print the workflow instead of writing it")
+	f.BoolVar(&newForce, "force", false, "overwrite an existing file")
+	rootCmd.AddCommand(newCmd)
+}
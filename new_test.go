@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ashleywolf/gh-aw-create/internal/config"
+	"github.com/ashleywolf/gh-aw-create/internal/policy"
+)
+
+func TestNewPolicyViolation(t *testing.T) {
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, dir := range []string{".git", ".github", "sub"} {
+		if err := os.Mkdir(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pol := "triggers:\n  deny: [issues]\n  reason: no issue workflows\n"
+	if err := os.WriteFile(filepath.Join(repo, policy.File), []byte(pol), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// From a subdirectory the policy at the repository root still applies.
+	t.Chdir(filepath.Join(repo, "sub"))
+
+	rootCmd.SetArgs([]string{"new", "issue-triage", "--trigger", "issues"})
+	rootCmd.SetOut(&strings.Builder{})
+	rootCmd.SetErr(&strings.Builder{})
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "triggers.deny") {
+		t.Fatalf("new = %v, want a triggers.deny violation", err)
+	}
+
+	for _, dir := range []string{repo, filepath.Join(repo, "sub")} {
+		matches, _ := filepath.Glob(filepath.Join(dir, ".github", "workflows", "*"))
+		if len(matches) > 0 {
+			t.Errorf("new wrote %q despite the violation", matches)
+		}
+	}
+}
+
+func TestNewFromSubdirectory(t *testing.T) {
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	for _, dir := range []string{".git", ".github", "sub"} {
+		if err := os.Mkdir(filepath.Join(repo, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, config.RepoFile), []byte("output_dir: agents\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(filepath.Join(repo, "sub"))
+
+	rootCmd.SetArgs([]string{"new", "issue-triage", "--trigger", "issues", "--name", "triage"})
+	rootCmd.SetOut(&strings.Builder{})
+	rootCmd.SetErr(&strings.Builder{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	// The team's output_dir is relative to the repository root.
+	if _, err := os.Stat(filepath.Join(repo, "agents", "triage.md")); err != nil {
+		t.Errorf("workflow not written at the repository root: %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(repo, "sub", "*")); len(matches) > 0 {
+		t.Errorf("new wrote %q in the working directory", matches)
+	}
+}